@@ -0,0 +1,73 @@
+package address
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"syscall"
+)
+
+var ErrUnsupportedScheme = errors.New("unsupported address scheme")
+var ErrSocketInUse = errors.New("socket is in use by another process")
+
+// Parse Splits an address into the network and address expected by net.Dial.
+// It accepts unix:///path/to/socket, tcp://host:port, http://host:port and
+// plain host:port.
+func Parse(address string) (network string, addr string, err error) {
+	scheme, rest, found := strings.Cut(address, "://")
+	if !found {
+		return "tcp", address, nil
+	}
+	switch scheme {
+	case "unix":
+		return "unix", rest, nil
+	case "tcp", "http":
+		return "tcp", strings.TrimSuffix(rest, "/"), nil
+	}
+	return "", "", ErrUnsupportedScheme
+}
+
+// Listen Listens on address. Unix sockets are created with the given file
+// mode. A socket file left behind by a previous run, which refuses
+// connections, is replaced; one a running process still accepts connections
+// on is reported with ErrSocketInUse.
+func Listen(address string, mode os.FileMode) (net.Listener, error) {
+	network, addr, err := Parse(address)
+	if err != nil {
+		return nil, err
+	}
+	if network != "unix" {
+		return net.Listen(network, addr)
+	}
+
+	if info, err := os.Lstat(addr); err == nil && info.Mode()&os.ModeSocket != 0 {
+		if err := removeStaleSocket(addr); err != nil {
+			return nil, err
+		}
+	}
+	listener, err := net.Listen(network, addr)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.Chmod(addr, mode); err != nil {
+		listener.Close()
+		return nil, err
+	}
+	return listener, nil
+}
+
+// removeStaleSocket Removes the socket file at path when nothing listens on
+// it any more
+func removeStaleSocket(path string) error {
+	conn, err := net.Dial("unix", path)
+	if err == nil {
+		conn.Close()
+		return fmt.Errorf("%w: %s", ErrSocketInUse, path)
+	}
+	if !errors.Is(err, syscall.ECONNREFUSED) {
+		return err
+	}
+	return os.Remove(path)
+}
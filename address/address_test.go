@@ -0,0 +1,114 @@
+package address
+
+import (
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	testCases := []struct {
+		address string
+		network string
+		addr    string
+	}{
+		{"unix:///run/idgen.sock", "unix", "/run/idgen.sock"},
+		{"tcp://127.0.0.1:1323", "tcp", "127.0.0.1:1323"},
+		{"http://localhost:1323/", "tcp", "localhost:1323"},
+		{":1323", "tcp", ":1323"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.address, func(t *testing.T) {
+			network, addr, err := Parse(tc.address)
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if network != tc.network || addr != tc.addr {
+				t.Errorf("Expected %s %s, got %s %s", tc.network, tc.addr, network, addr)
+			}
+		})
+	}
+}
+
+func TestParse_UnsupportedScheme(t *testing.T) {
+	if _, _, err := Parse("udp://localhost:1323"); !errors.Is(err, ErrUnsupportedScheme) {
+		t.Errorf("Expected ErrUnsupportedScheme, got %v", err)
+	}
+}
+
+func TestListen_Unix(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "idgen.sock")
+
+	listener, err := Listen("unix://"+path, 0600)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	defer listener.Close()
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatalf("Expected socket file, got %v", err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("Expected mode 0600, got %v", info.Mode().Perm())
+	}
+
+	conn, err := net.Dial("unix", path)
+	if err != nil {
+		t.Fatalf("Failed to dial socket: %v", err)
+	}
+	conn.Close()
+}
+
+func TestListen_ReplacesStaleSocket(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "idgen.sock")
+
+	// Leave a socket file behind, as a crashed process would
+	stale, err := net.Listen("unix", path)
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	stale.(*net.UnixListener).SetUnlinkOnClose(false)
+	stale.Close()
+
+	listener, err := Listen("unix://"+path, 0660)
+	if err != nil {
+		t.Fatalf("Expected stale socket to be replaced, got %v", err)
+	}
+	listener.Close()
+}
+
+func TestListen_KeepsSocketInUse(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "idgen.sock")
+
+	running, err := Listen("unix://"+path, 0660)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	defer running.Close()
+
+	if _, err := Listen("unix://"+path, 0660); !errors.Is(err, ErrSocketInUse) {
+		t.Errorf("Expected ErrSocketInUse, got %v", err)
+	}
+
+	// The running listener still owns the socket
+	conn, err := net.Dial("unix", path)
+	if err != nil {
+		t.Fatalf("Expected the socket to stay reachable, got %v", err)
+	}
+	conn.Close()
+}
+
+func TestListen_KeepsRegularFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "idgen.sock")
+	if err := os.WriteFile(path, []byte("data"), 0600); err != nil {
+		t.Fatalf("Failed to write file: %v", err)
+	}
+
+	if _, err := Listen("unix://"+path, 0660); err == nil {
+		t.Error("Expected error when a regular file is in the way")
+	}
+}
@@ -0,0 +1,78 @@
+package client
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"uidGenerator/generator"
+	"uidGenerator/handler"
+	generatorMiddleware "uidGenerator/middleware"
+	"uidGenerator/timeprovider/epoch"
+
+	"github.com/labstack/echo/v4"
+)
+
+func BenchmarkLatency_HTTP(b *testing.B) {
+	e := echo.New()
+	e.Use(generatorMiddleware.PoolProvider(generator.NewPool(1, epoch.New(1420070400000))))
+	e.GET("/", handler.Generator)
+	server := httptest.NewServer(e)
+	defer server.Close()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		resp, err := http.Get(server.URL + "/")
+		if err != nil {
+			b.Fatalf("Unexpected error: %v", err)
+		}
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if _, err := Decode(resp.Header.Get("Content-Type"), body); err != nil {
+			b.Errorf("Unexpected error: %v", err)
+		}
+	}
+}
+
+func BenchmarkLatency_TCP(b *testing.B) {
+	address := startTCPServer(b, generator.NewPool(1, epoch.New(1420070400000)))
+	c, err := DialTCP(address)
+	if err != nil {
+		b.Fatalf("Failed to dial: %v", err)
+	}
+	defer c.Close()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := c.Generate(1); err != nil {
+			b.Errorf("Unexpected error: %v", err)
+		}
+	}
+}
+
+func BenchmarkLatency_TCP_Pipelined(b *testing.B) {
+	address := startTCPServer(b, generator.NewPool(1, epoch.New(1420070400000)))
+	c, err := DialTCP(address)
+	if err != nil {
+		b.Fatalf("Failed to dial: %v", err)
+	}
+	defer c.Close()
+
+	const depth = 16
+	b.ResetTimer()
+	for i := 0; i < b.N; i += depth {
+		for j := 0; j < depth; j++ {
+			if err := c.Send(1); err != nil {
+				b.Fatalf("Unexpected error: %v", err)
+			}
+		}
+		if err := c.Flush(); err != nil {
+			b.Fatalf("Unexpected error: %v", err)
+		}
+		for j := 0; j < depth; j++ {
+			if _, err := c.Receive(); err != nil {
+				b.Errorf("Unexpected error: %v", err)
+			}
+		}
+	}
+}
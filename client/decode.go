@@ -0,0 +1,38 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"mime"
+	"uidGenerator/codec"
+)
+
+var ErrUnsupportedContentType = errors.New("unsupported content type")
+
+// Decode Returns the IDs carried by a response body of the given content type
+func Decode(contentType string, body []byte) ([]int64, error) {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return nil, err
+	}
+
+	if format, ok := codec.Lookup(mediaType); ok {
+		return format.Unmarshal(body)
+	}
+
+	if mediaType != "application/json" {
+		return nil, ErrUnsupportedContentType
+	}
+
+	var response struct {
+		Ids   []int64 `json:"ids"`
+		Error string  `json:"error"`
+	}
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, err
+	}
+	if response.Error != "" {
+		return nil, errors.New(response.Error)
+	}
+	return response.Ids, nil
+}
@@ -0,0 +1,65 @@
+package client
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"uidGenerator/codec"
+	"uidGenerator/generator"
+	"uidGenerator/handler"
+	"uidGenerator/timeprovider/epoch"
+
+	"github.com/labstack/echo/v4"
+)
+
+func TestDecode_RoundTrip(t *testing.T) {
+	e := echo.New()
+	provider := epoch.New(1420070400000)
+	worker := &generator.WorkerVariant{
+		WorkerID:     1,
+		ThreadId:     1,
+		TimeProvider: provider,
+	}
+
+	for _, accept := range append([]string{"application/json"}, codec.MediaTypes()...) {
+		t.Run(accept, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/?numberOfIds=50", nil)
+			req.Header.Set(echo.HeaderAccept, accept)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+			c.Set("worker", worker)
+
+			if err := handler.Generator(c); err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+
+			ids, err := Decode(rec.Header().Get(echo.HeaderContentType), rec.Body.Bytes())
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if len(ids) != 50 {
+				t.Errorf("Expected 50 IDs, got %d", len(ids))
+			}
+			for i := 1; i < len(ids); i++ {
+				if ids[i] <= ids[i-1] {
+					t.Errorf("Expected increasing IDs, got %d after %d", ids[i], ids[i-1])
+				}
+			}
+		})
+	}
+}
+
+func TestDecode_ErrorResponse(t *testing.T) {
+	_, err := Decode("application/json; charset=UTF-8", []byte(`{"error":"invalid previous time stamp"}`))
+	if err == nil || err.Error() != "invalid previous time stamp" {
+		t.Errorf("Expected server error to be returned, got %v", err)
+	}
+}
+
+func TestDecode_UnsupportedContentType(t *testing.T) {
+	_, err := Decode("text/plain", []byte("1"))
+	if !errors.Is(err, ErrUnsupportedContentType) {
+		t.Errorf("Expected ErrUnsupportedContentType, got %v", err)
+	}
+}
@@ -0,0 +1,63 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"uidGenerator/address"
+	"uidGenerator/codec"
+)
+
+// HTTPClient Calls the HTTP API over TCP or a Unix domain socket
+type HTTPClient struct {
+	baseURL    string
+	httpClient *http.Client
+}
+
+// NewHTTP Creates a client for the server listening on address, which is
+// either http://host:port, host:port or unix:///path/to/socket
+func NewHTTP(serverAddress string) (*HTTPClient, error) {
+	network, addr, err := address.Parse(serverAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	if network != "unix" {
+		return &HTTPClient{baseURL: "http://" + addr, httpClient: http.DefaultClient}, nil
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DialContext = func(ctx context.Context, _, _ string) (net.Conn, error) {
+		var dialer net.Dialer
+		return dialer.DialContext(ctx, network, addr)
+	}
+	return &HTTPClient{baseURL: "http://unix", httpClient: &http.Client{Transport: transport}}, nil
+}
+
+// Generate Requests a batch of IDs
+func (c *HTTPClient) Generate(numberOfIds int) ([]int64, error) {
+	req, err := http.NewRequest(http.MethodGet, c.baseURL+"/?numberOfIds="+strconv.Itoa(numberOfIds), nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", codec.MIMEOctetStream)
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	ids, err := Decode(resp.Header.Get("Content-Type"), body)
+	if err == nil && resp.StatusCode != http.StatusOK {
+		err = fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	return ids, err
+}
@@ -0,0 +1,60 @@
+package client
+
+import (
+	"net/http"
+	"path/filepath"
+	"testing"
+	"uidGenerator/address"
+	"uidGenerator/generator"
+	"uidGenerator/handler"
+	generatorMiddleware "uidGenerator/middleware"
+	"uidGenerator/timeprovider/epoch"
+
+	"github.com/labstack/echo/v4"
+)
+
+func startHTTPServer(t *testing.T, serverAddress string) string {
+	listener, err := address.Listen(serverAddress, 0600)
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+
+	e := echo.New()
+	e.Use(generatorMiddleware.PoolProvider(generator.NewPool(1, epoch.New(1420070400000))))
+	e.GET("/", handler.Generator)
+	server := &http.Server{Handler: e}
+	go server.Serve(listener)
+	t.Cleanup(func() { server.Close() })
+
+	if listener.Addr().Network() == "unix" {
+		return "unix://" + listener.Addr().String()
+	}
+	return "http://" + listener.Addr().String()
+}
+
+func TestHTTPClient_Generate(t *testing.T) {
+	testCases := []struct {
+		name    string
+		address string
+	}{
+		{"TCP", "127.0.0.1:0"},
+		{"Unix", "unix://" + filepath.Join(t.TempDir(), "idgen.sock")},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			c, err := NewHTTP(startHTTPServer(t, tc.address))
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+
+			ids, err := c.Generate(10)
+			if err != nil {
+				t.Fatalf("Expected no error, got %v", err)
+			}
+			if len(ids) != 10 {
+				t.Errorf("Expected 10 IDs, got %d", len(ids))
+			}
+		})
+	}
+}
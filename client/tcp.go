@@ -0,0 +1,97 @@
+package client
+
+import (
+	"bufio"
+	"encoding/binary"
+	"net"
+	"uidGenerator/address"
+	"uidGenerator/codec"
+	"uidGenerator/tcp"
+)
+
+// TCPClient Speaks the binary protocol of the tcp package. It is not safe for
+// concurrent use, open one client per goroutine.
+type TCPClient struct {
+	conn  net.Conn
+	r     *bufio.Reader
+	w     *bufio.Writer
+	frame []byte
+}
+
+// DialTCP Connects to the binary protocol listener at host:port or
+// unix:///path/to/socket
+func DialTCP(serverAddress string) (*TCPClient, error) {
+	network, addr, err := address.Parse(serverAddress)
+	if err != nil {
+		return nil, err
+	}
+	conn, err := net.Dial(network, addr)
+	if err != nil {
+		return nil, err
+	}
+	return NewTCPClient(conn), nil
+}
+
+// NewTCPClient Wraps an established connection
+func NewTCPClient(conn net.Conn) *TCPClient {
+	return &TCPClient{
+		conn: conn,
+		r:    bufio.NewReader(conn),
+		w:    bufio.NewWriter(conn),
+	}
+}
+
+// Generate Requests a batch of IDs and waits for it
+func (c *TCPClient) Generate(numberOfIds int) ([]int64, error) {
+	if err := c.Send(numberOfIds); err != nil {
+		return nil, err
+	}
+	if err := c.Flush(); err != nil {
+		return nil, err
+	}
+	return c.Receive()
+}
+
+// Send Buffers a request for 1 to tcp.MaxBatch IDs. Several requests may be
+// sent before their answers are read with Receive, in the same order.
+func (c *TCPClient) Send(numberOfIds int) error {
+	if numberOfIds <= 0 {
+		return &tcp.StatusError{Status: tcp.StatusBadRequest, Message: "invalid batch size"}
+	}
+	if numberOfIds > tcp.MaxBatch {
+		return &tcp.StatusError{Status: tcp.StatusBadRequest, Message: "batch too large"}
+	}
+
+	var request [8]byte
+	binary.BigEndian.PutUint32(request[:], 4)
+	binary.BigEndian.PutUint32(request[4:], uint32(numberOfIds))
+	_, err := c.w.Write(request[:])
+	return err
+}
+
+// Flush Writes the buffered requests to the connection
+func (c *TCPClient) Flush() error {
+	return c.w.Flush()
+}
+
+// Receive Reads the answer to the oldest outstanding request
+func (c *TCPClient) Receive() ([]int64, error) {
+	frame, err := tcp.ReadFrame(c.r, c.frame)
+	if err != nil {
+		return nil, err
+	}
+	c.frame = frame
+
+	if len(frame) == 0 {
+		return nil, &tcp.StatusError{Status: tcp.StatusBadRequest, Message: "empty response"}
+	}
+	if frame[0] != tcp.StatusOK {
+		return nil, &tcp.StatusError{Status: frame[0], Message: string(frame[1:])}
+	}
+	return codec.UnmarshalFixed(frame[1:])
+}
+
+// Close Closes the connection
+func (c *TCPClient) Close() error {
+	return c.conn.Close()
+}
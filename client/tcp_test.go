@@ -0,0 +1,132 @@
+package client
+
+import (
+	"errors"
+	"net"
+	"path/filepath"
+	"testing"
+	"uidGenerator/address"
+	"uidGenerator/generator"
+	"uidGenerator/tcp"
+	"uidGenerator/timeprovider/epoch"
+)
+
+func startTCPServer(t testing.TB, pool *generator.Pool) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	go tcp.Serve(listener, pool)
+	t.Cleanup(func() { listener.Close() })
+	return listener.Addr().String()
+}
+
+func TestTCPClient_Generate(t *testing.T) {
+	address := startTCPServer(t, generator.NewPool(1, epoch.New(1420070400000)))
+	c, err := DialTCP(address)
+	if err != nil {
+		t.Fatalf("Failed to dial: %v", err)
+	}
+	defer c.Close()
+
+	ids, err := c.Generate(10)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(ids) != 10 {
+		t.Errorf("Expected 10 IDs, got %d", len(ids))
+	}
+}
+
+func TestTCPClient_Pipelined(t *testing.T) {
+	address := startTCPServer(t, generator.NewPool(1, epoch.New(1420070400000)))
+	c, err := DialTCP(address)
+	if err != nil {
+		t.Fatalf("Failed to dial: %v", err)
+	}
+	defer c.Close()
+
+	for i := 1; i <= 5; i++ {
+		if err := c.Send(i); err != nil {
+			t.Fatalf("Failed to send: %v", err)
+		}
+	}
+	if err := c.Flush(); err != nil {
+		t.Fatalf("Failed to flush: %v", err)
+	}
+
+	idMap := make(map[int64]bool)
+	for i := 1; i <= 5; i++ {
+		ids, err := c.Receive()
+		if err != nil {
+			t.Fatalf("Failed to receive: %v", err)
+		}
+		if len(ids) != i {
+			t.Errorf("Expected %d IDs, got %d", i, len(ids))
+		}
+		for _, id := range ids {
+			if idMap[id] {
+				t.Errorf("Duplicate ID found: %d", id)
+			}
+			idMap[id] = true
+		}
+	}
+}
+
+func TestTCPClient_StatusError(t *testing.T) {
+	address := startTCPServer(t, generator.NewPool(1, epoch.New(1420070400000)))
+	c, err := DialTCP(address)
+	if err != nil {
+		t.Fatalf("Failed to dial: %v", err)
+	}
+	defer c.Close()
+
+	_, err = c.Generate(tcp.MaxBatch + 1)
+	var statusErr *tcp.StatusError
+	if !errors.As(err, &statusErr) || statusErr.Status != tcp.StatusBadRequest {
+		t.Errorf("Expected bad request status, got %v", err)
+	}
+}
+
+func TestTCPClient_SendInvalidBatch(t *testing.T) {
+	server, conn := net.Pipe()
+	defer server.Close()
+	c := NewTCPClient(conn)
+	defer c.Close()
+
+	// 1<<32 + 1 would wrap to a request for a single ID
+	for _, numberOfIds := range []int{0, -1, tcp.MaxBatch + 1, 1<<32 + 1} {
+		err := c.Send(numberOfIds)
+		var statusErr *tcp.StatusError
+		if !errors.As(err, &statusErr) || statusErr.Status != tcp.StatusBadRequest {
+			t.Errorf("Expected bad request status for %d, got %v", numberOfIds, err)
+		}
+	}
+	if buffered := c.w.Buffered(); buffered != 0 {
+		t.Errorf("Expected nothing buffered, got %d bytes", buffered)
+	}
+}
+
+func TestDialTCP_Unix(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "idgen.sock")
+	listener, err := address.Listen("unix://"+path, 0600)
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	defer listener.Close()
+	go tcp.Serve(listener, generator.NewPool(1, epoch.New(1420070400000)))
+
+	c, err := DialTCP("unix://" + path)
+	if err != nil {
+		t.Fatalf("Failed to dial: %v", err)
+	}
+	defer c.Close()
+
+	ids, err := c.Generate(3)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(ids) != 3 {
+		t.Errorf("Expected 3 IDs, got %d", len(ids))
+	}
+}
@@ -0,0 +1,86 @@
+package codec
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func benchmarkIds() []int64 {
+	ids := make([]int64, 1000)
+	for i := range ids {
+		ids[i] = 1234567890123456789 + int64(i)
+	}
+	return ids
+}
+
+func BenchmarkEncode_JSON(b *testing.B) {
+	ids := benchmarkIds()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, err := json.Marshal(map[string]interface{}{"ids": ids})
+		if err != nil {
+			b.Errorf("Unexpected error: %v", err)
+		}
+	}
+}
+
+func BenchmarkEncode_Fixed(b *testing.B) {
+	ids := benchmarkIds()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		MarshalFixed(ids)
+	}
+}
+
+func BenchmarkEncode_DeltaVarint(b *testing.B) {
+	ids := benchmarkIds()
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		MarshalDeltaVarint(ids)
+	}
+}
+
+func BenchmarkDecode_JSON(b *testing.B) {
+	data, _ := json.Marshal(map[string]interface{}{"ids": benchmarkIds()})
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		var response struct {
+			Ids []int64 `json:"ids"`
+		}
+		if err := json.Unmarshal(data, &response); err != nil {
+			b.Errorf("Unexpected error: %v", err)
+		}
+	}
+}
+
+func BenchmarkDecode_Fixed(b *testing.B) {
+	data := MarshalFixed(benchmarkIds())
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := UnmarshalFixed(data); err != nil {
+			b.Errorf("Unexpected error: %v", err)
+		}
+	}
+}
+
+func BenchmarkDecode_DeltaVarint(b *testing.B) {
+	data := MarshalDeltaVarint(benchmarkIds())
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := UnmarshalDeltaVarint(data); err != nil {
+			b.Errorf("Unexpected error: %v", err)
+		}
+	}
+}
@@ -0,0 +1,100 @@
+package codec
+
+import (
+	"encoding/binary"
+	"errors"
+)
+
+const (
+	// MIMEOctetStream is served as big-endian fixed 8-byte IDs
+	MIMEOctetStream = "application/octet-stream"
+	// MIMEProtobuf is served as a protobuf message whose field 1 holds the
+	// packed, zigzag encoded deltas between consecutive IDs:
+	//
+	//	message IDs { repeated sint64 deltas = 1 [packed = true]; }
+	MIMEProtobuf = "application/x-protobuf"
+)
+
+// protobuf tag of field 1 with wire type 2 (length delimited)
+const deltasTag = 1<<3 | 2
+
+var ErrTruncated = errors.New("truncated payload")
+var ErrMalformed = errors.New("malformed payload")
+
+// MarshalFixed Encodes every ID as 8 big-endian bytes
+func MarshalFixed(ids []int64) []byte {
+	return AppendFixed(make([]byte, 0, len(ids)*8), ids)
+}
+
+// AppendFixed Appends the fixed 8-byte encoding of ids to dst
+func AppendFixed(dst []byte, ids []int64) []byte {
+	for _, id := range ids {
+		dst = binary.BigEndian.AppendUint64(dst, uint64(id))
+	}
+	return dst
+}
+
+// UnmarshalFixed Decodes a payload produced by MarshalFixed
+func UnmarshalFixed(data []byte) ([]int64, error) {
+	if len(data)%8 != 0 {
+		return nil, ErrTruncated
+	}
+	ids := make([]int64, 0, len(data)/8)
+	for i := 0; i < len(data); i += 8 {
+		ids = append(ids, int64(binary.BigEndian.Uint64(data[i:])))
+	}
+	return ids, nil
+}
+
+// MarshalDeltaVarint Encodes ids as a protobuf message of packed deltas.
+// IDs from one batch share most of their high bits, so deltas are small and
+// usually fit in one or two bytes.
+func MarshalDeltaVarint(ids []int64) []byte {
+	body := make([]byte, 0, len(ids)*2+binary.MaxVarintLen64)
+	var previous int64
+	for _, id := range ids {
+		body = binary.AppendVarint(body, id-previous)
+		previous = id
+	}
+
+	data := make([]byte, 0, len(body)+1+binary.MaxVarintLen64)
+	if len(ids) == 0 {
+		return data
+	}
+	data = append(data, deltasTag)
+	data = binary.AppendUvarint(data, uint64(len(body)))
+	return append(data, body...)
+}
+
+// UnmarshalDeltaVarint Decodes a payload produced by MarshalDeltaVarint
+func UnmarshalDeltaVarint(data []byte) ([]int64, error) {
+	var ids []int64
+	var previous int64
+	for len(data) > 0 {
+		if data[0] != deltasTag {
+			return nil, ErrMalformed
+		}
+		length, n := binary.Uvarint(data[1:])
+		if n <= 0 {
+			return nil, ErrMalformed
+		}
+		data = data[1+n:]
+		if uint64(len(data)) < length {
+			return nil, ErrTruncated
+		}
+
+		// Packed fields may be split over several records, deltas continue across them
+		body := data[:length]
+		data = data[length:]
+		for len(body) > 0 {
+			delta, n := binary.Varint(body)
+			if n <= 0 {
+				return nil, ErrMalformed
+			}
+			previous += delta
+			ids = append(ids, previous)
+			body = body[n:]
+		}
+	}
+	return ids, nil
+}
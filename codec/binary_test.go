@@ -0,0 +1,51 @@
+package codec
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestFixed_BigEndian(t *testing.T) {
+	data := MarshalFixed([]int64{1})
+	expected := []byte{0, 0, 0, 0, 0, 0, 0, 1}
+	if !reflect.DeepEqual(data, expected) {
+		t.Errorf("Expected %v, got %v", expected, data)
+	}
+}
+
+func TestFixed_Truncated(t *testing.T) {
+	_, err := UnmarshalFixed(make([]byte, 7))
+	if !errors.Is(err, ErrTruncated) {
+		t.Errorf("Expected ErrTruncated, got %v", err)
+	}
+}
+
+func TestDeltaVarint_Empty(t *testing.T) {
+	if data := MarshalDeltaVarint(nil); len(data) != 0 {
+		t.Errorf("Expected empty payload, got %v", data)
+	}
+	ids, err := UnmarshalDeltaVarint(nil)
+	if err != nil || len(ids) != 0 {
+		t.Errorf("Expected no IDs and no error, got %v and %v", ids, err)
+	}
+}
+
+func TestDeltaVarint_Compact(t *testing.T) {
+	data := MarshalDeltaVarint(sampleIds)
+	if len(data) >= len(MarshalFixed(sampleIds)) {
+		t.Errorf("Expected delta encoding (%d bytes) to be smaller than fixed", len(data))
+	}
+}
+
+func TestDeltaVarint_Malformed(t *testing.T) {
+	data := MarshalDeltaVarint(sampleIds)
+
+	if _, err := UnmarshalDeltaVarint(data[:len(data)-1]); !errors.Is(err, ErrTruncated) {
+		t.Errorf("Expected ErrTruncated, got %v", err)
+	}
+
+	if _, err := UnmarshalDeltaVarint([]byte{0x08, 0x01}); !errors.Is(err, ErrMalformed) {
+		t.Errorf("Expected ErrMalformed for unknown tag, got %v", err)
+	}
+}
@@ -0,0 +1,80 @@
+package codec
+
+import "encoding/binary"
+
+// MIMECBOR is served as a CBOR map {"ids": [uint64...]}
+const MIMECBOR = "application/cbor"
+
+const (
+	cborUint  = 0 << 5
+	cborText  = 3 << 5
+	cborArray = 4 << 5
+	cborMap   = 5 << 5
+)
+
+// MarshalCBOR Encodes ids as unsigned integers so no precision is lost
+func MarshalCBOR(ids []int64) []byte {
+	data := make([]byte, 0, 14+len(ids)*9)
+	data = appendCBORHead(data, cborMap, 1)
+	data = appendCBORHead(data, cborText, 3)
+	data = append(data, "ids"...)
+	data = appendCBORHead(data, cborArray, uint64(len(ids)))
+	for _, id := range ids {
+		data = appendCBORHead(data, cborUint, uint64(id))
+	}
+	return data
+}
+
+func appendCBORHead(data []byte, major byte, v uint64) []byte {
+	switch {
+	case v < 24:
+		return append(data, major|byte(v))
+	case v <= 0xff:
+		return append(data, major|24, byte(v))
+	case v <= 0xffff:
+		return binary.BigEndian.AppendUint16(append(data, major|25), uint16(v))
+	case v <= 0xffffffff:
+		return binary.BigEndian.AppendUint32(append(data, major|26), uint32(v))
+	}
+	return binary.BigEndian.AppendUint64(append(data, major|27), v)
+}
+
+func readCBORHead(r *reader, major byte) uint64 {
+	b := r.byte()
+	if b&0xe0 != major {
+		r.fail()
+		return 0
+	}
+	switch info := b & 0x1f; {
+	case info < 24:
+		return uint64(info)
+	case info == 24:
+		return uint64(r.byte())
+	case info == 25:
+		return uint64(binary.BigEndian.Uint16(r.next(2)))
+	case info == 26:
+		return uint64(binary.BigEndian.Uint32(r.next(4)))
+	case info == 27:
+		return binary.BigEndian.Uint64(r.next(8))
+	}
+	r.fail()
+	return 0
+}
+
+// UnmarshalCBOR Decodes a payload produced by MarshalCBOR
+func UnmarshalCBOR(data []byte) ([]int64, error) {
+	r := reader{data: data}
+	if readCBORHead(&r, cborMap) != 1 || readCBORHead(&r, cborText) != 3 || string(r.next(3)) != "ids" {
+		return nil, r.fail()
+	}
+
+	n := readCBORHead(&r, cborArray)
+	ids := make([]int64, 0, min(n, uint64(len(data))))
+	for i := uint64(0); i < n && r.err == nil; i++ {
+		ids = append(ids, int64(readCBORHead(&r, cborUint)))
+	}
+	if err := r.end(); err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
@@ -0,0 +1,35 @@
+package codec
+
+// Format pairs a media type with the functions that encode and decode it
+type Format struct {
+	MIME      string
+	Marshal   func(ids []int64) []byte
+	Unmarshal func(data []byte) ([]int64, error)
+}
+
+// Formats Lists every binary encoding the API can serve
+var Formats = []Format{
+	{MIME: MIMEOctetStream, Marshal: MarshalFixed, Unmarshal: UnmarshalFixed},
+	{MIME: MIMEProtobuf, Marshal: MarshalDeltaVarint, Unmarshal: UnmarshalDeltaVarint},
+	{MIME: MIMEMsgpack, Marshal: MarshalMsgpack, Unmarshal: UnmarshalMsgpack},
+	{MIME: MIMECBOR, Marshal: MarshalCBOR, Unmarshal: UnmarshalCBOR},
+}
+
+// MediaTypes Returns the media types of all Formats
+func MediaTypes() []string {
+	mediaTypes := make([]string, len(Formats))
+	for i, format := range Formats {
+		mediaTypes[i] = format.MIME
+	}
+	return mediaTypes
+}
+
+// Lookup Returns the format registered for mediaType
+func Lookup(mediaType string) (Format, bool) {
+	for _, format := range Formats {
+		if format.MIME == mediaType {
+			return format, true
+		}
+	}
+	return Format{}, false
+}
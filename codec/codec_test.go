@@ -0,0 +1,123 @@
+package codec
+
+import (
+	"errors"
+	"math"
+	"reflect"
+	"testing"
+)
+
+var sampleIds = []int64{
+	1234567890123456789,
+	1234567890123456790,
+	1234567890123456791,
+	1234567890123457813,
+}
+
+func TestFormats_RoundTrip(t *testing.T) {
+	sequential := make([]int64, 70000)
+	for i := range sequential {
+		sequential[i] = int64(i) << 20
+	}
+
+	testCases := []struct {
+		name string
+		ids  []int64
+	}{
+		{"Sample", sampleIds},
+		{"Empty", []int64{}},
+		{"Single", []int64{42}},
+		{"Descending", []int64{100, 50, 0}},
+		{"Widths", []int64{0x7f, 0xff, 0xffff, 0xffffffff, math.MaxInt64}},
+		{"Extremes", []int64{math.MaxInt64, 0, math.MinInt64}},
+		{"Large", sequential},
+	}
+
+	for _, format := range Formats {
+		for _, tc := range testCases {
+			t.Run(format.MIME+"/"+tc.name, func(t *testing.T) {
+				ids, err := format.Unmarshal(format.Marshal(tc.ids))
+				if err != nil {
+					t.Fatalf("Expected no error, got %v", err)
+				}
+				if len(ids) != len(tc.ids) || (len(ids) > 0 && !reflect.DeepEqual(ids, tc.ids)) {
+					t.Errorf("Expected %d IDs to round trip, got %d", len(tc.ids), len(ids))
+				}
+			})
+		}
+	}
+}
+
+func TestFormats_Truncated(t *testing.T) {
+	for _, format := range Formats {
+		t.Run(format.MIME, func(t *testing.T) {
+			data := format.Marshal(sampleIds)
+			if _, err := format.Unmarshal(data[:len(data)-1]); !errors.Is(err, ErrTruncated) {
+				t.Errorf("Expected ErrTruncated, got %v", err)
+			}
+		})
+	}
+}
+
+func TestLookup(t *testing.T) {
+	for _, mediaType := range MediaTypes() {
+		format, ok := Lookup(mediaType)
+		if !ok || format.MIME != mediaType {
+			t.Errorf("Expected format for %s", mediaType)
+		}
+	}
+
+	if _, ok := Lookup("application/json"); ok {
+		t.Error("Expected no binary format for JSON")
+	}
+}
+
+func TestMsgpack_Encoding(t *testing.T) {
+	// {"ids": [1, 4294967296]}
+	expected := []byte{
+		0x81, 0xa3, 'i', 'd', 's', 0x92,
+		0x01,
+		0xcf, 0x00, 0x00, 0x00, 0x01, 0x00, 0x00, 0x00, 0x00,
+	}
+	data := MarshalMsgpack([]int64{1, 1 << 32})
+	if !reflect.DeepEqual(data, expected) {
+		t.Errorf("Expected %x, got %x", expected, data)
+	}
+}
+
+func TestCBOR_Encoding(t *testing.T) {
+	// {"ids": [1, 4294967296]}
+	expected := []byte{
+		0xa1, 0x63, 'i', 'd', 's', 0x82,
+		0x01,
+		0x1b, 0x00, 0x00, 0x00, 0x01, 0x00, 0x00, 0x00, 0x00,
+	}
+	data := MarshalCBOR([]int64{1, 1 << 32})
+	if !reflect.DeepEqual(data, expected) {
+		t.Errorf("Expected %x, got %x", expected, data)
+	}
+}
+
+func TestMsgpack_Malformed(t *testing.T) {
+	// Array of strings instead of integers
+	if _, err := UnmarshalMsgpack([]byte{0x81, 0xa3, 'i', 'd', 's', 0x91, 0xa1, 'x'}); !errors.Is(err, ErrMalformed) {
+		t.Errorf("Expected ErrMalformed, got %v", err)
+	}
+}
+
+func TestCBOR_Malformed(t *testing.T) {
+	// Negative integer instead of unsigned
+	if _, err := UnmarshalCBOR([]byte{0xa1, 0x63, 'i', 'd', 's', 0x81, 0x20}); !errors.Is(err, ErrMalformed) {
+		t.Errorf("Expected ErrMalformed, got %v", err)
+	}
+}
+
+func TestUnmarshal_TrailingBytes(t *testing.T) {
+	ids := []int64{1, 1 << 32}
+	if _, err := UnmarshalMsgpack(append(MarshalMsgpack(ids), 0x01)); !errors.Is(err, ErrMalformed) {
+		t.Errorf("Expected ErrMalformed for msgpack, got %v", err)
+	}
+	if _, err := UnmarshalCBOR(append(MarshalCBOR(ids), 0x01)); !errors.Is(err, ErrMalformed) {
+		t.Errorf("Expected ErrMalformed for CBOR, got %v", err)
+	}
+}
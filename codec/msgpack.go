@@ -0,0 +1,82 @@
+package codec
+
+import "encoding/binary"
+
+// MIMEMsgpack is served as a MessagePack map {"ids": [uint64...]}
+const MIMEMsgpack = "application/msgpack"
+
+// MarshalMsgpack Encodes ids as unsigned integers so no precision is lost
+func MarshalMsgpack(ids []int64) []byte {
+	data := make([]byte, 0, 10+len(ids)*9)
+	data = append(data, 0x81, 0xa3, 'i', 'd', 's')
+	switch n := len(ids); {
+	case n < 16:
+		data = append(data, 0x90|byte(n))
+	case n <= 0xffff:
+		data = binary.BigEndian.AppendUint16(append(data, 0xdc), uint16(n))
+	default:
+		data = binary.BigEndian.AppendUint32(append(data, 0xdd), uint32(n))
+	}
+	for _, id := range ids {
+		data = appendMsgpackUint(data, uint64(id))
+	}
+	return data
+}
+
+func appendMsgpackUint(data []byte, v uint64) []byte {
+	switch {
+	case v < 0x80:
+		return append(data, byte(v))
+	case v <= 0xff:
+		return append(data, 0xcc, byte(v))
+	case v <= 0xffff:
+		return binary.BigEndian.AppendUint16(append(data, 0xcd), uint16(v))
+	case v <= 0xffffffff:
+		return binary.BigEndian.AppendUint32(append(data, 0xce), uint32(v))
+	}
+	return binary.BigEndian.AppendUint64(append(data, 0xcf), v)
+}
+
+// UnmarshalMsgpack Decodes a payload produced by MarshalMsgpack
+func UnmarshalMsgpack(data []byte) ([]int64, error) {
+	r := reader{data: data}
+	if r.byte() != 0x81 || r.byte() != 0xa3 || string(r.next(3)) != "ids" {
+		return nil, r.fail()
+	}
+
+	var n int
+	switch b := r.byte(); {
+	case b&0xf0 == 0x90:
+		n = int(b & 0x0f)
+	case b == 0xdc:
+		n = int(binary.BigEndian.Uint16(r.next(2)))
+	case b == 0xdd:
+		n = int(binary.BigEndian.Uint32(r.next(4)))
+	default:
+		return nil, r.fail()
+	}
+
+	ids := make([]int64, 0, min(n, len(data)))
+	for i := 0; i < n && r.err == nil; i++ {
+		var v uint64
+		switch b := r.byte(); {
+		case b < 0x80:
+			v = uint64(b)
+		case b == 0xcc:
+			v = uint64(r.byte())
+		case b == 0xcd:
+			v = uint64(binary.BigEndian.Uint16(r.next(2)))
+		case b == 0xce:
+			v = uint64(binary.BigEndian.Uint32(r.next(4)))
+		case b == 0xcf:
+			v = binary.BigEndian.Uint64(r.next(8))
+		default:
+			return nil, r.fail()
+		}
+		ids = append(ids, int64(v))
+	}
+	if err := r.end(); err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
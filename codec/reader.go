@@ -0,0 +1,42 @@
+package codec
+
+// reader Walks a payload and remembers the first error, so decoders can read
+// a whole structure and check for failure once
+type reader struct {
+	data []byte
+	err  error
+}
+
+func (r *reader) next(n int) []byte {
+	if r.err != nil {
+		return make([]byte, n)
+	}
+	if len(r.data) < n {
+		r.err = ErrTruncated
+		return make([]byte, n)
+	}
+	b := r.data[:n]
+	r.data = r.data[n:]
+	return b
+}
+
+func (r *reader) byte() byte {
+	return r.next(1)[0]
+}
+
+// end Returns the first error, or ErrMalformed when bytes are left after
+// the structure
+func (r *reader) end() error {
+	if r.err == nil && len(r.data) > 0 {
+		r.err = ErrMalformed
+	}
+	return r.err
+}
+
+// fail Marks the payload as malformed unless it was already found truncated
+func (r *reader) fail() error {
+	if r.err == nil {
+		r.err = ErrMalformed
+	}
+	return r.err
+}
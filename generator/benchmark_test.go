@@ -1,6 +1,7 @@
 package generator
 
 import (
+	"runtime"
 	"testing"
 	"uidGenerator/timeprovider/epoch"
 	"uidGenerator/timeprovider/julian"
@@ -14,6 +15,7 @@ func BenchmarkGenerateID_Single_Epoch(b *testing.B) {
 		TimeProvider: provider,
 	}
 	
+	b.ReportAllocs()
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
 		_, err := worker.GenerateID(1)
@@ -31,6 +33,7 @@ func BenchmarkGenerateID_Single_Julian(b *testing.B) {
 		TimeProvider: provider,
 	}
 	
+	b.ReportAllocs()
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
 		_, err := worker.GenerateID(1)
@@ -48,6 +51,7 @@ func BenchmarkGenerateID_Multiple_Epoch(b *testing.B) {
 		TimeProvider: provider,
 	}
 	
+	b.ReportAllocs()
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
 		_, err := worker.GenerateID(10)
@@ -60,6 +64,7 @@ func BenchmarkGenerateID_Multiple_Epoch(b *testing.B) {
 func BenchmarkGenerateID_Multiple_Julian(b *testing.B) {
 	provider := julian.New(2000100000)
 	
+	b.ReportAllocs()
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
 		// Create a new worker for each iteration to avoid counter exhaustion
@@ -83,6 +88,7 @@ func BenchmarkGenerateID_Large_Batch(b *testing.B) {
 		TimeProvider: provider,
 	}
 	
+	b.ReportAllocs()
 	b.ResetTimer()
 	for i := 0; i < b.N; i++ {
 		_, err := worker.GenerateID(100)
@@ -91,3 +97,63 @@ func BenchmarkGenerateID_Large_Batch(b *testing.B) {
 		}
 	}
 }
+
+func BenchmarkGenerateInto_Single_Epoch(b *testing.B) {
+	provider := epoch.New(1420070400000)
+	worker := &WorkerVariant{
+		WorkerID:     1,
+		ThreadId:     1,
+		TimeProvider: provider,
+	}
+	dst := make([]int64, 1)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, err := worker.GenerateInto(dst)
+		if err != nil {
+			b.Errorf("Unexpected error: %v", err)
+		}
+	}
+}
+
+func BenchmarkGenerateInto_Large_Batch(b *testing.B) {
+	provider := epoch.New(1420070400000)
+	worker := &WorkerVariant{
+		WorkerID:     1,
+		ThreadId:     1,
+		TimeProvider: provider,
+	}
+	dst := make([]int64, 100)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, err := worker.GenerateInto(dst)
+		if err != nil {
+			b.Errorf("Unexpected error: %v", err)
+		}
+	}
+}
+
+func benchmarkPoolParallel(b *testing.B, shards int) {
+	pool := NewShardedPool(1, epoch.New(1420070400000), shards)
+	b.ReportAllocs()
+	b.ResetTimer()
+	b.RunParallel(func(pb *testing.PB) {
+		dst := make([]int64, 1)
+		for pb.Next() {
+			if _, err := pool.GenerateInto(dst); err != nil {
+				b.Errorf("Unexpected error: %v", err)
+			}
+		}
+	})
+}
+
+func BenchmarkPool_Parallel_SingleShard(b *testing.B) {
+	benchmarkPoolParallel(b, 1)
+}
+
+func BenchmarkPool_Parallel_Sharded(b *testing.B) {
+	benchmarkPoolParallel(b, runtime.GOMAXPROCS(0))
+}
@@ -0,0 +1,162 @@
+package generator
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+	"uidGenerator/timeprovider/fake"
+)
+
+func TestPool_Borrowing(t *testing.T) {
+	// The clock never advances, so without borrowing a batch larger than one
+	// counter would wait forever
+	provider := fake.NewClock(500)
+	pool := NewShardedPool(1, provider, 4)
+	pool.EnableBorrowing()
+
+	capacity := int(ThreadCap * (MaxCounter + 1))
+	ids, err := pool.GenerateID(capacity)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	idMap := make(map[int64]bool)
+	threads := make(map[int64]bool)
+	for _, id := range ids {
+		if idMap[id] {
+			t.Fatalf("Duplicate ID found: %d", id)
+		}
+		idMap[id] = true
+
+		components := Decode(id)
+		if components.Timestamp != 500 {
+			t.Errorf("Expected timestamp 500, got %d", components.Timestamp)
+		}
+		threads[components.ThreadId] = true
+	}
+	if int64(len(threads)) != ThreadCap {
+		t.Errorf("Expected every thread to lend its counter, got %d threads", len(threads))
+	}
+
+	// All workers are back in the pool
+	var workers []*WorkerVariant
+	for i := int64(0); i < ThreadCap; i++ {
+		workers = append(workers, pool.Acquire())
+	}
+	if pool.tryAcquire() != nil {
+		t.Error("Expected no more workers than thread IDs")
+	}
+	for _, worker := range workers {
+		pool.Release(worker)
+	}
+}
+
+func TestPool_BorrowingSkipsBusyWorkers(t *testing.T) {
+	provider := fake.NewClock(500)
+	pool := NewPool(1, provider)
+	pool.EnableBorrowing()
+
+	// Hold all but two workers, so only one can lend
+	var held []*WorkerVariant
+	for i := int64(0); i < ThreadCap-2; i++ {
+		held = append(held, pool.Acquire())
+	}
+
+	ids, err := pool.GenerateID(int(2 * (MaxCounter + 1)))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	threads := make(map[int64]bool)
+	for _, id := range ids {
+		threads[Decode(id).ThreadId] = true
+	}
+	if len(threads) != 2 {
+		t.Errorf("Expected IDs from 2 threads, got %d", len(threads))
+	}
+
+	for _, worker := range held {
+		pool.Release(worker)
+	}
+}
+
+// countingClock Counts the reads of a fake clock
+type countingClock struct {
+	*fake.Clock
+	calls atomic.Int64
+}
+
+func (c *countingClock) GetTimeStamp() int64 {
+	c.calls.Add(1)
+	return c.Clock.GetTimeStamp()
+}
+
+func TestPool_BorrowingReleasesDrainedLenders(t *testing.T) {
+	provider := &countingClock{Clock: fake.NewClock(500)}
+	pool := NewPoolOf([]*WorkerVariant{
+		{WorkerID: 1, ThreadId: 1, TimeProvider: provider},
+		{WorkerID: 1, ThreadId: 2, TimeProvider: provider},
+		{WorkerID: 1, ThreadId: 3, TimeProvider: provider},
+	})
+	pool.EnableBorrowing()
+
+	// One more ID than the counters of all workers hold, so the batch waits
+	// for the next timestamp
+	done := make(chan error, 1)
+	go func() {
+		_, err := pool.GenerateID(int(3*(MaxCounter+1)) + 1)
+		done <- err
+	}()
+
+	// One read per worker fills the counters, later reads wait for the clock
+	deadline := time.Now().Add(time.Second)
+	for provider.calls.Load() <= 4 && time.Now().Before(deadline) {
+		time.Sleep(time.Millisecond)
+	}
+
+	// The lenders are free while it waits
+	var lenders []*WorkerVariant
+	for worker := pool.tryAcquire(); worker != nil; worker = pool.tryAcquire() {
+		lenders = append(lenders, worker)
+	}
+	if len(lenders) != 2 {
+		t.Errorf("Expected 2 lenders to be released while the batch waits, got %d", len(lenders))
+	}
+	pool.releaseAll(lenders)
+
+	provider.Set(501)
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("Expected no error, got %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected the batch to complete after the clock moved")
+	}
+}
+
+func TestGenerateAvailable(t *testing.T) {
+	provider := fake.NewClock(500)
+	worker := &WorkerVariant{
+		WorkerID:     1,
+		ThreadId:     1,
+		TimeProvider: provider,
+	}
+
+	dst := make([]int64, MaxCounter+10)
+	n, err := worker.generateAvailable(dst)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if int64(n) != MaxCounter+1 {
+		t.Errorf("Expected %d IDs, got %d", MaxCounter+1, n)
+	}
+
+	// The counter stays exhausted until the clock moves
+	if n, _ := worker.generateAvailable(dst); n != 0 {
+		t.Errorf("Expected no IDs from an exhausted counter, got %d", n)
+	}
+	provider.Set(501)
+	if n, _ := worker.generateAvailable(dst[:5]); n != 5 {
+		t.Errorf("Expected 5 IDs after the clock moved, got %d", n)
+	}
+}
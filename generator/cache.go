@@ -0,0 +1,170 @@
+package generator
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// refillChunk bounds how many IDs a refill generates per worker acquisition,
+// so requests are not kept waiting for a free worker
+const refillChunk = 256
+
+// CacheConfig Configures the IDs a pool keeps pre-generated
+type CacheConfig struct {
+	LowWatermark   int           // Refill starts when fewer IDs are cached
+	HighWatermark  int           // Refill stops once this many IDs are cached
+	MaxAge         time.Duration // Time since caching after which IDs are dropped, never when 0
+	RefillInterval time.Duration // How often the cache is checked while idle
+}
+
+// CacheStats Counts how IDs were served since the cache was enabled
+type CacheStats struct {
+	Hits   int64 `json:"hits"`   // IDs served from the cache
+	Misses int64 `json:"misses"` // IDs generated on request
+	Size   int   `json:"size"`   // IDs currently cached
+}
+
+// HitRate Returns the share of IDs served from the cache
+func (s CacheStats) HitRate() float64 {
+	if s.Hits+s.Misses == 0 {
+		return 0
+	}
+	return float64(s.Hits) / float64(s.Hits+s.Misses)
+}
+
+// Cache Pre-generates IDs while the pool is idle, so bursts do not have to
+// wait for the counter to roll over into the next timestamp. Cached IDs are
+// unique, but a batch mixing cached and fresh IDs is not necessarily sorted.
+type Cache struct {
+	config CacheConfig
+	pool   *Pool
+	now    func() time.Time
+	refill chan struct{}
+
+	mutex    sync.Mutex
+	ids      []int64
+	cachedAt []time.Time // When each ID was cached, read from the system clock
+
+	hits   atomic.Int64
+	misses atomic.Int64
+}
+
+// EnableCache Puts a cache in front of the pool and refills it in the
+// background until ctx is done. It must be called before the pool is used.
+func (p *Pool) EnableCache(ctx context.Context, config CacheConfig) *Cache {
+	if config.LowWatermark > config.HighWatermark {
+		config.LowWatermark = config.HighWatermark
+	}
+	if config.RefillInterval <= 0 {
+		config.RefillInterval = 10 * time.Millisecond
+	}
+
+	cache := &Cache{
+		config:   config,
+		pool:     p,
+		now:      time.Now,
+		refill:   make(chan struct{}, 1),
+		ids:      make([]int64, 0, config.HighWatermark),
+		cachedAt: make([]time.Time, 0, config.HighWatermark),
+	}
+	p.cache = cache
+	go cache.run(ctx)
+	return cache
+}
+
+// Stats Returns the hit and miss counts and the current size
+func (c *Cache) Stats() CacheStats {
+	c.mutex.Lock()
+	size := len(c.ids)
+	c.mutex.Unlock()
+	return CacheStats{
+		Hits:   c.hits.Load(),
+		Misses: c.misses.Load(),
+		Size:   size,
+	}
+}
+
+// take Moves as many fresh cached IDs into dst as available
+func (c *Cache) take(dst []int64) int {
+	c.mutex.Lock()
+	c.dropStale()
+	n := copy(dst, c.ids)
+	c.ids = c.ids[n:]
+	c.cachedAt = c.cachedAt[n:]
+	low := len(c.ids) < c.config.LowWatermark
+	c.mutex.Unlock()
+
+	c.hits.Add(int64(n))
+	c.misses.Add(int64(len(dst) - n))
+	if low {
+		select {
+		case c.refill <- struct{}{}:
+		default:
+		}
+	}
+	return n
+}
+
+// dropStale Removes IDs cached longer than MaxAge ago, the oldest are at the
+// front. The age is measured on the system clock rather than the time
+// provider, as reading providers such as the hybrid logical clock or the
+// Lamport counter advances them, and the timestamps of the latter carry no
+// time at all.
+func (c *Cache) dropStale() {
+	if c.config.MaxAge <= 0 || len(c.ids) == 0 {
+		return
+	}
+	oldest := c.now().Add(-c.config.MaxAge)
+	stale := 0
+	for stale < len(c.ids) && c.cachedAt[stale].Before(oldest) {
+		stale++
+	}
+	c.ids = c.ids[stale:]
+	c.cachedAt = c.cachedAt[stale:]
+}
+
+func (c *Cache) run(ctx context.Context) {
+	ticker := time.NewTicker(c.config.RefillInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-c.refill:
+		}
+		if ctx.Err() != nil {
+			return
+		}
+		c.fill()
+	}
+}
+
+// fill Tops the cache up to the high watermark once it fell below the low one
+func (c *Cache) fill() {
+	c.mutex.Lock()
+	c.dropStale()
+	missing := c.config.HighWatermark - len(c.ids)
+	if len(c.ids) >= c.config.LowWatermark {
+		missing = 0
+	}
+	c.mutex.Unlock()
+
+	var chunk [refillChunk]int64
+	for missing > 0 {
+		n, err := c.pool.generateInto(chunk[:min(missing, refillChunk)])
+		if err != nil {
+			return
+		}
+		c.mutex.Lock()
+		c.ids = append(c.ids, chunk[:n]...)
+		now := c.now()
+		for range n {
+			c.cachedAt = append(c.cachedAt, now)
+		}
+		c.mutex.Unlock()
+		missing -= n
+	}
+}
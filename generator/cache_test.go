@@ -0,0 +1,144 @@
+package generator
+
+import (
+	"context"
+	"testing"
+	"time"
+	"uidGenerator/timeprovider/epoch"
+	"uidGenerator/timeprovider/fake"
+)
+
+func waitForCacheSize(t *testing.T, cache *Cache, size int) {
+	deadline := time.Now().Add(time.Second)
+	for cache.Stats().Size < size {
+		if time.Now().After(deadline) {
+			t.Fatalf("Expected cache to reach %d IDs, got %d", size, cache.Stats().Size)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestCache_ServesBurst(t *testing.T) {
+	pool := NewPool(1, epoch.New(1420070400000))
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	cache := pool.EnableCache(ctx, CacheConfig{LowWatermark: 1000, HighWatermark: 2000})
+	waitForCacheSize(t, cache, 2000)
+
+	ids, err := pool.GenerateID(1500)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	idMap := make(map[int64]bool)
+	for _, id := range ids {
+		if idMap[id] {
+			t.Errorf("Duplicate ID found: %d", id)
+		}
+		idMap[id] = true
+	}
+
+	stats := cache.Stats()
+	if stats.Hits != 1500 || stats.Misses != 0 {
+		t.Errorf("Expected 1500 hits and no misses, got %+v", stats)
+	}
+	if stats.HitRate() != 1 {
+		t.Errorf("Expected hit rate 1, got %v", stats.HitRate())
+	}
+
+	// Falling below the low watermark triggers a refill
+	waitForCacheSize(t, cache, 2000)
+}
+
+func TestCache_PartialHit(t *testing.T) {
+	pool := NewPool(1, epoch.New(1420070400000))
+	// Without background refills the cache is filled once by hand
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	cache := pool.EnableCache(ctx, CacheConfig{LowWatermark: 10, HighWatermark: 10})
+	cache.fill()
+
+	ids, err := pool.GenerateID(15)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if len(ids) != 15 {
+		t.Errorf("Expected 15 IDs, got %d", len(ids))
+	}
+
+	stats := cache.Stats()
+	if stats.Hits != 10 || stats.Misses != 5 {
+		t.Errorf("Expected 10 hits and 5 misses, got %+v", stats)
+	}
+	if rate := stats.HitRate(); rate < 0.66 || rate > 0.67 {
+		t.Errorf("Expected hit rate 2/3, got %v", rate)
+	}
+}
+
+func TestCache_DropsStaleIds(t *testing.T) {
+	provider := fake.NewClock(1000)
+	pool := NewPool(1, provider)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	cache := pool.EnableCache(ctx, CacheConfig{LowWatermark: 100, HighWatermark: 100, MaxAge: 50 * time.Millisecond})
+	now := time.Unix(0, 0)
+	cache.now = func() time.Time { return now }
+	cache.fill()
+
+	// Still within MaxAge
+	now = now.Add(50 * time.Millisecond)
+	ids, err := pool.GenerateID(10)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if cache.Stats().Hits != 10 {
+		t.Errorf("Expected 10 hits, got %+v", cache.Stats())
+	}
+
+	// Too old to be served
+	now = now.Add(time.Millisecond)
+	provider.Set(1051)
+	ids, err = pool.GenerateID(10)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	for _, id := range ids {
+		if Decode(id).Timestamp != 1051 {
+			t.Errorf("Expected fresh ID, got timestamp %d", Decode(id).Timestamp)
+		}
+	}
+	if stats := cache.Stats(); stats.Hits != 10 || stats.Misses != 10 || stats.Size != 0 {
+		t.Errorf("Expected stale IDs to be dropped, got %+v", stats)
+	}
+}
+
+func TestCache_AgeDoesNotReadProvider(t *testing.T) {
+	provider := &countingClock{Clock: fake.NewClock(1000)}
+	pool := NewPool(1, provider)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	cache := pool.EnableCache(ctx, CacheConfig{LowWatermark: 100, HighWatermark: 100, MaxAge: time.Hour})
+	cache.fill()
+
+	// Reading clocks like the hybrid logical clock advances them, so IDs
+	// served from the cache must not touch the provider
+	calls := provider.calls.Load()
+	if _, err := pool.GenerateID(10); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if cache.Stats().Hits != 10 {
+		t.Errorf("Expected 10 hits, got %+v", cache.Stats())
+	}
+	if after := provider.calls.Load(); after != calls {
+		t.Errorf("Expected no reads of the provider, got %d", after-calls)
+	}
+}
+
+func TestCacheStats_HitRateEmpty(t *testing.T) {
+	if rate := (CacheStats{}).HitRate(); rate != 0 {
+		t.Errorf("Expected hit rate 0, got %v", rate)
+	}
+}
@@ -0,0 +1,15 @@
+package generator
+
+// Components Holds the fields packed into an ID
+type Components struct {
+	Timestamp int64 `json:"timestamp"`
+	WorkerID  int64 `json:"workerId"`
+	ThreadId  int64 `json:"threadId"`
+	Counter   int64 `json:"counter"`
+}
+
+// Decode Splits an ID of the default layout back into the fields GenerateID
+// packed into it
+func Decode(id int64) Components {
+	return DefaultLayout().Decode(id)
+}
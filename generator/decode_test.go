@@ -0,0 +1,46 @@
+package generator
+
+import (
+	"testing"
+	"uidGenerator/timeprovider/epoch"
+)
+
+func TestDecode(t *testing.T) {
+	provider := epoch.New(1420070400000)
+	worker := &WorkerVariant{
+		WorkerID:     5,
+		ThreadId:     3,
+		TimeProvider: provider,
+	}
+
+	before := provider.GetTimeStamp()
+	ids, err := worker.GenerateID(3)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	after := provider.GetTimeStamp()
+
+	for i, id := range ids {
+		components := Decode(id)
+		if components.Timestamp < before || components.Timestamp > after {
+			t.Errorf("Expected timestamp between %d and %d, got %d", before, after, components.Timestamp)
+		}
+		if components.WorkerID != 5 {
+			t.Errorf("Expected worker ID 5, got %d", components.WorkerID)
+		}
+		if components.ThreadId != 3 {
+			t.Errorf("Expected thread ID 3, got %d", components.ThreadId)
+		}
+		if i > 0 && components.Counter != Decode(ids[i-1]).Counter+1 && components.Counter != 0 {
+			t.Errorf("Expected consecutive counters, got %d after %d", components.Counter, Decode(ids[i-1]).Counter)
+		}
+	}
+}
+
+func TestDecode_Fields(t *testing.T) {
+	id := int64(12345)<<(NodeIdBits+ThreadBits+CounterBitSize) | 7<<(ThreadBits+CounterBitSize) | 31<<CounterBitSize | 1023
+	expected := Components{Timestamp: 12345, WorkerID: 7, ThreadId: 31, Counter: 1023}
+	if components := Decode(id); components != expected {
+		t.Errorf("Expected %+v, got %+v", expected, components)
+	}
+}
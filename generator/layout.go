@@ -0,0 +1,70 @@
+package generator
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Layout Describes how many bits of an ID each field occupies, from the
+// most significant timestamp down to the counter
+type Layout struct {
+	TimestampBits int64
+	WorkerBits    int64
+	ThreadBits    int64
+	CounterBits   int64
+}
+
+// DefaultLayout Returns the layout described by the package bit sizes
+func DefaultLayout() Layout {
+	return Layout{
+		TimestampBits: EpochBits,
+		WorkerBits:    NodeIdBits,
+		ThreadBits:    ThreadBits,
+		CounterBits:   CounterBitSize,
+	}
+}
+
+// Validate Checks that every field has room and the ID stays positive
+func (l Layout) Validate() error {
+	if l.TimestampBits <= 0 || l.WorkerBits < 0 || l.ThreadBits <= 0 || l.CounterBits <= 0 {
+		return errors.New("layout fields must have positive sizes")
+	}
+	if total := l.TimestampBits + l.WorkerBits + l.ThreadBits + l.CounterBits; total > 63 {
+		return fmt.Errorf("layout uses %d bits, at most 63 are available", total)
+	}
+	return nil
+}
+
+// MaxWorkerID Returns the largest worker ID the layout can hold
+func (l Layout) MaxWorkerID() int64 {
+	return (1 << l.WorkerBits) - 1
+}
+
+// MaxThreadID Returns the largest thread ID the layout can hold
+func (l Layout) MaxThreadID() int64 {
+	return (1 << l.ThreadBits) - 1
+}
+
+// MaxCounter Returns the largest counter value the layout can hold
+func (l Layout) MaxCounter() int64 {
+	return (1 << l.CounterBits) - 1
+}
+
+// Compose Packs the fields into an ID
+func (l Layout) Compose(timestamp, workerId, threadId, counter int64) int64 {
+	id := timestamp << (l.WorkerBits + l.ThreadBits + l.CounterBits)
+	id |= workerId << (l.ThreadBits + l.CounterBits)
+	id |= threadId << l.CounterBits
+	id |= counter
+	return id
+}
+
+// Decode Splits an ID back into the fields Compose packed into it
+func (l Layout) Decode(id int64) Components {
+	return Components{
+		Timestamp: id >> (l.WorkerBits + l.ThreadBits + l.CounterBits),
+		WorkerID:  (id >> (l.ThreadBits + l.CounterBits)) & l.MaxWorkerID(),
+		ThreadId:  (id >> l.CounterBits) & l.MaxThreadID(),
+		Counter:   id & l.MaxCounter(),
+	}
+}
@@ -0,0 +1,58 @@
+package generator
+
+import "testing"
+
+func TestDefaultLayout(t *testing.T) {
+	layout := DefaultLayout()
+	if layout.MaxWorkerID() != MaxNodeId {
+		t.Errorf("Expected max worker ID %d, got %d", MaxNodeId, layout.MaxWorkerID())
+	}
+	if layout.MaxThreadID() != ThreadCap {
+		t.Errorf("Expected max thread ID %d, got %d", ThreadCap, layout.MaxThreadID())
+	}
+	if layout.MaxCounter() != MaxCounter {
+		t.Errorf("Expected max counter %d, got %d", MaxCounter, layout.MaxCounter())
+	}
+	if err := layout.Validate(); err != nil {
+		t.Errorf("Expected default layout to be valid, got %v", err)
+	}
+}
+
+func TestLayout_Validate(t *testing.T) {
+	testCases := []struct {
+		name   string
+		layout Layout
+		valid  bool
+	}{
+		{"Sonyflake", Layout{TimestampBits: 39, WorkerBits: 16, ThreadBits: 1, CounterBits: 7}, true},
+		{"Single node", Layout{TimestampBits: 41, WorkerBits: 0, ThreadBits: 5, CounterBits: 12}, true},
+		{"Too wide", Layout{TimestampBits: 42, WorkerBits: 10, ThreadBits: 5, CounterBits: 7}, false},
+		{"No counter", Layout{TimestampBits: 41, WorkerBits: 3, ThreadBits: 5, CounterBits: 0}, false},
+		{"No threads", Layout{TimestampBits: 41, WorkerBits: 3, ThreadBits: 0, CounterBits: 10}, false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.layout.Validate()
+			if tc.valid && err != nil {
+				t.Errorf("Expected layout to be valid, got %v", err)
+			}
+			if !tc.valid && err == nil {
+				t.Error("Expected layout to be invalid")
+			}
+		})
+	}
+}
+
+func TestLayout_ComposeDecode(t *testing.T) {
+	layout := Layout{TimestampBits: 39, WorkerBits: 16, ThreadBits: 1, CounterBits: 7}
+	expected := Components{Timestamp: 1 << 38, WorkerID: 65535, ThreadId: 1, Counter: 127}
+
+	id := layout.Compose(expected.Timestamp, expected.WorkerID, expected.ThreadId, expected.Counter)
+	if id <= 0 {
+		t.Errorf("Expected positive ID, got %d", id)
+	}
+	if components := layout.Decode(id); components != expected {
+		t.Errorf("Expected %+v, got %+v", expected, components)
+	}
+}
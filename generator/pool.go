@@ -0,0 +1,231 @@
+package generator
+
+import (
+	"sync"
+	"sync/atomic"
+	"uidGenerator/timeprovider"
+)
+
+// Pool Hands out the workers of one node so that each thread ID is used by a
+// single caller at a time. The workers may be split into shards, each owning
+// a disjoint set of thread IDs, so parallel callers do not all contend on one
+// channel.
+type Pool struct {
+	shards []chan *WorkerVariant
+	cache  *Cache
+	borrow bool
+
+	// wake Is signalled by Release, so callers waiting while every shard is
+	// empty search them again
+	wake chan struct{}
+
+	// hints Hands out home shards. sync.Pool keeps its objects per P, so a
+	// goroutine mostly gets the hint last used on the same P.
+	hints    sync.Pool
+	nextHint atomic.Uint32
+}
+
+// NewPool Creates a worker for every thread ID of the node
+func NewPool(workerId int64, provider timeprovider.TimeProvider) *Pool {
+	return NewShardedPool(workerId, provider, 1)
+}
+
+// NewShardedPool Creates a worker for every thread ID of the node, split
+// into the given number of shards
+func NewShardedPool(workerId int64, provider timeprovider.TimeProvider, shards int) *Pool {
+	workers := make([]*WorkerVariant, 0, ThreadCap)
+	var i int64
+	for i = 1; i <= ThreadCap; i++ {
+		worker := &WorkerVariant{
+			WorkerID:             workerId,
+			ThreadId:             i,
+			TimeProvider:         provider,
+			FallibleTimeProvider: timeprovider.Adapt(provider),
+		}
+		workers = append(workers, worker)
+	}
+	return NewShardedPoolOf(workers, shards)
+}
+
+// NewPoolOf Creates a pool handing out the given workers, which must have
+// distinct thread IDs
+func NewPoolOf(workers []*WorkerVariant) *Pool {
+	return NewShardedPoolOf(workers, 1)
+}
+
+// NewShardedPoolOf Creates a pool handing out the given workers, dealt round
+// robin into the given number of shards. There are never more shards than
+// workers.
+func NewShardedPoolOf(workers []*WorkerVariant, shards int) *Pool {
+	shards = max(min(shards, len(workers)), 1)
+	pool := &Pool{shards: make([]chan *WorkerVariant, shards), wake: make(chan struct{}, 1)}
+	for i := range pool.shards {
+		pool.shards[i] = make(chan *WorkerVariant, (len(workers)+shards-1)/shards)
+	}
+	for i, worker := range workers {
+		worker.shard = i % shards
+		pool.shards[worker.shard] <- worker
+	}
+	pool.hints.New = func() interface{} {
+		hint := int(pool.nextHint.Add(1)-1) % shards
+		return &hint
+	}
+	return pool
+}
+
+// Acquire Blocks until a worker is free and hands it to the caller. Workers
+// come from the caller's home shard, other shards are searched when it is
+// exhausted, and when every shard is empty the caller waits for a worker to
+// be released to any of them.
+func (p *Pool) Acquire() *WorkerVariant {
+	if len(p.shards) == 1 {
+		return <-p.shards[0]
+	}
+
+	hint := p.hints.Get().(*int)
+	home := *hint
+	p.hints.Put(hint)
+
+	woken := false
+	for {
+		for i := range p.shards {
+			select {
+			case worker := <-p.shards[(home+i)%len(p.shards)]:
+				// Several workers may have been released under one signal,
+				// pass it on to the next waiter
+				if woken {
+					p.signal()
+				}
+				return worker
+			default:
+			}
+		}
+
+		select {
+		case worker := <-p.shards[home]:
+			return worker
+		case <-p.wake:
+			woken = true
+		}
+	}
+}
+
+// Release Returns a worker obtained from Acquire to the shard owning it
+func (p *Pool) Release(worker *WorkerVariant) {
+	p.shards[worker.shard] <- worker
+	if len(p.shards) > 1 {
+		p.signal()
+	}
+}
+
+// signal Wakes a caller waiting in Acquire, unless a wake-up is pending
+func (p *Pool) signal() {
+	select {
+	case p.wake <- struct{}{}:
+	default:
+	}
+}
+
+// GenerateID Generates up to MaxBatch IDs on whichever worker is free,
+// serving them from the cache first when one is enabled
+func (p *Pool) GenerateID(numberOfIds int) ([]int64, error) {
+	if numberOfIds <= 0 {
+		numberOfIds = 1
+	}
+	if numberOfIds > MaxBatch {
+		return nil, ErrBatchTooLarge
+	}
+	ids := make([]int64, numberOfIds)
+	if _, err := p.GenerateInto(ids); err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// GenerateInto Fills dst with IDs on whichever worker is free, serving them
+// from the cache first when one is enabled
+func (p *Pool) GenerateInto(dst []int64) (int, error) {
+	n := 0
+	if p.cache != nil {
+		n = p.cache.take(dst)
+		if n == len(dst) {
+			return n, nil
+		}
+	}
+	m, err := p.generateInto(dst[n:])
+	return n + m, err
+}
+
+// GenerateAfter Fills dst with increasing IDs greater than after on
+// whichever worker is free, bypassing the cache, so a caller holding no
+// worker can keep its IDs strictly increasing across calls
+func (p *Pool) GenerateAfter(dst []int64, after int64) (int, error) {
+	worker := p.Acquire()
+	defer p.Release(worker)
+	return worker.GenerateAfter(dst, after)
+}
+
+// EnableBorrowing Lets a batch that exhausts the counter of its worker take
+// the rest from the counters of idle workers in the same timestamp, instead
+// of waiting for the next one. IDs stay unique since every worker has its own
+// thread ID, but such a batch is no longer sorted. It must be called before
+// the pool is used.
+func (p *Pool) EnableBorrowing() {
+	p.borrow = true
+}
+
+// generateInto Fills dst on a worker, bypassing the cache
+func (p *Pool) generateInto(dst []int64) (int, error) {
+	worker := p.Acquire()
+	defer p.Release(worker)
+	if !p.borrow {
+		return worker.GenerateInto(dst)
+	}
+
+	n, err := worker.generateAvailable(dst)
+	if err != nil || n == len(dst) {
+		return n, err
+	}
+
+	// Lenders are kept while borrowing, so a drained one is not picked
+	// again, and go back before waiting for the next timestamp, so a large
+	// batch does not hold the node while it waits
+	var lenders []*WorkerVariant
+	for n < len(dst) {
+		lender := p.tryAcquire()
+		if lender == nil {
+			break
+		}
+		lenders = append(lenders, lender)
+		m, err := lender.generateAvailable(dst[n:])
+		if err != nil {
+			p.releaseAll(lenders)
+			return n, err
+		}
+		n += m
+	}
+	p.releaseAll(lenders)
+
+	// Every idle counter is exhausted, wait for the next timestamp
+	m, err := worker.GenerateInto(dst[n:])
+	return n + m, err
+}
+
+// releaseAll Returns the workers to their shards
+func (p *Pool) releaseAll(workers []*WorkerVariant) {
+	for _, worker := range workers {
+		p.Release(worker)
+	}
+}
+
+// tryAcquire Returns a free worker of any shard, or nil when all are busy
+func (p *Pool) tryAcquire() *WorkerVariant {
+	for _, shard := range p.shards {
+		select {
+		case worker := <-shard:
+			return worker
+		default:
+		}
+	}
+	return nil
+}
@@ -0,0 +1,196 @@
+package generator
+
+import (
+	"sync"
+	"testing"
+	"time"
+	"uidGenerator/timeprovider/epoch"
+)
+
+func TestNewPool(t *testing.T) {
+	provider := epoch.New(1420070400000)
+	pool := NewPool(3, provider)
+
+	threadIds := make(map[int64]bool)
+	var workers []*WorkerVariant
+	for i := int64(0); i < ThreadCap; i++ {
+		worker := pool.Acquire()
+		if worker.WorkerID != 3 {
+			t.Errorf("Expected worker ID 3, got %d", worker.WorkerID)
+		}
+		if worker.TimeProvider != provider {
+			t.Error("Time provider not correctly set")
+		}
+		threadIds[worker.ThreadId] = true
+		workers = append(workers, worker)
+	}
+
+	if int64(len(threadIds)) != ThreadCap {
+		t.Errorf("Expected %d distinct thread IDs, got %d", ThreadCap, len(threadIds))
+	}
+
+	for _, worker := range workers {
+		pool.Release(worker)
+	}
+}
+
+func TestPool_GenerateID_Concurrent(t *testing.T) {
+	pool := NewPool(1, epoch.New(1420070400000))
+
+	var mutex sync.Mutex
+	var wg sync.WaitGroup
+	idMap := make(map[int64]bool)
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ids, err := pool.GenerateID(20)
+			if err != nil {
+				t.Errorf("Expected no error, got %v", err)
+				return
+			}
+			mutex.Lock()
+			defer mutex.Unlock()
+			for _, id := range ids {
+				if idMap[id] {
+					t.Errorf("Duplicate ID found: %d", id)
+				}
+				idMap[id] = true
+			}
+		}()
+	}
+	wg.Wait()
+
+	if len(idMap) != 2000 {
+		t.Errorf("Expected 2000 IDs, got %d", len(idMap))
+	}
+}
+
+func TestNewShardedPool(t *testing.T) {
+	pool := NewShardedPool(1, epoch.New(1420070400000), 4)
+	if len(pool.shards) != 4 {
+		t.Fatalf("Expected 4 shards, got %d", len(pool.shards))
+	}
+
+	// Every thread ID is owned by exactly one shard
+	threadIds := make(map[int64]int)
+	for i, shard := range pool.shards {
+		for len(shard) > 0 {
+			worker := <-shard
+			if worker.shard != i {
+				t.Errorf("Worker %d is in shard %d but owned by %d", worker.ThreadId, i, worker.shard)
+			}
+			threadIds[worker.ThreadId]++
+		}
+	}
+	if int64(len(threadIds)) != ThreadCap {
+		t.Errorf("Expected %d thread IDs, got %d", ThreadCap, len(threadIds))
+	}
+	for threadId, count := range threadIds {
+		if count != 1 {
+			t.Errorf("Thread ID %d found %d times", threadId, count)
+		}
+	}
+}
+
+func TestShardedPool_ShardCount(t *testing.T) {
+	workers := []*WorkerVariant{{ThreadId: 1}, {ThreadId: 2}}
+	if pool := NewShardedPoolOf(workers, 8); len(pool.shards) != 2 {
+		t.Errorf("Expected shards to be capped at 2 workers, got %d", len(pool.shards))
+	}
+	if pool := NewShardedPoolOf(workers, 0); len(pool.shards) != 1 {
+		t.Errorf("Expected at least one shard, got %d", len(pool.shards))
+	}
+}
+
+func TestShardedPool_WorkStealing(t *testing.T) {
+	pool := NewShardedPool(1, epoch.New(1420070400000), 4)
+
+	// Every worker can be acquired although the home shard runs dry first
+	var workers []*WorkerVariant
+	for i := int64(0); i < ThreadCap; i++ {
+		workers = append(workers, pool.Acquire())
+	}
+
+	done := make(chan *WorkerVariant)
+	go func() {
+		done <- pool.Acquire()
+	}()
+
+	pool.Release(workers[0])
+	worker := <-done
+	pool.Release(worker)
+	for _, worker := range workers[1:] {
+		pool.Release(worker)
+	}
+}
+
+func TestShardedPool_WaitsOnEveryShard(t *testing.T) {
+	pool := NewShardedPool(1, epoch.New(1420070400000), 4)
+	// Every caller has shard 0 as its home
+	pool.hints = sync.Pool{New: func() interface{} { return new(int) }}
+
+	var workers []*WorkerVariant
+	for i := int64(0); i < ThreadCap; i++ {
+		workers = append(workers, pool.Acquire())
+	}
+
+	// Both waiters get a worker released to shards other than their home
+	done := make(chan *WorkerVariant)
+	for i := 0; i < 2; i++ {
+		go func() {
+			done <- pool.Acquire()
+		}()
+	}
+	// Let both block on their empty home shard first
+	time.Sleep(10 * time.Millisecond)
+	var released []*WorkerVariant
+	for _, worker := range workers {
+		if worker.shard != 0 && len(released) < 2 {
+			released = append(released, worker)
+			pool.Release(worker)
+		}
+	}
+	for i := 0; i < 2; i++ {
+		select {
+		case worker := <-done:
+			if worker.shard == 0 {
+				t.Errorf("Expected a worker of another shard, got shard %d", worker.shard)
+			}
+		case <-time.After(time.Second):
+			t.Fatal("Expected the waiter to get a worker released to another shard")
+		}
+	}
+}
+
+func TestShardedPool_GenerateID_Concurrent(t *testing.T) {
+	pool := NewShardedPool(1, epoch.New(1420070400000), 8)
+
+	var mutex sync.Mutex
+	var wg sync.WaitGroup
+	idMap := make(map[int64]bool)
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ids, err := pool.GenerateID(20)
+			if err != nil {
+				t.Errorf("Expected no error, got %v", err)
+				return
+			}
+			mutex.Lock()
+			defer mutex.Unlock()
+			for _, id := range ids {
+				if idMap[id] {
+					t.Errorf("Duplicate ID found: %d", id)
+				}
+				idMap[id] = true
+			}
+		}()
+	}
+	wg.Wait()
+
+	if len(idMap) != 2000 {
+		t.Errorf("Expected 2000 IDs, got %d", len(idMap))
+	}
+}
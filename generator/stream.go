@@ -0,0 +1,62 @@
+package generator
+
+import (
+	"context"
+	"iter"
+)
+
+// All Returns an endless sequence of IDs for range-over-func loops. The
+// sequence stops early when generation fails, e.g. because the clock went
+// backwards.
+func (w *WorkerVariant) All() iter.Seq[int64] {
+	return all(w.GenerateInto)
+}
+
+// Stream Generates IDs in the background, up to bufferSize ahead of the
+// consumer. The channel is closed when ctx is done or generation fails.
+// Buffered IDs carry the time they were generated at, not the time they are
+// received.
+func (w *WorkerVariant) Stream(ctx context.Context, bufferSize int) <-chan int64 {
+	return stream(ctx, bufferSize, w.GenerateInto)
+}
+
+// All Returns an endless sequence of IDs, each taken from whichever worker is
+// free
+func (p *Pool) All() iter.Seq[int64] {
+	return all(p.GenerateInto)
+}
+
+// Stream Generates IDs in the background like WorkerVariant.Stream, borrowing
+// a worker for every ID
+func (p *Pool) Stream(ctx context.Context, bufferSize int) <-chan int64 {
+	return stream(ctx, bufferSize, p.GenerateInto)
+}
+
+func all(generate func([]int64) (int, error)) iter.Seq[int64] {
+	return func(yield func(int64) bool) {
+		var id [1]int64
+		for {
+			if _, err := generate(id[:]); err != nil {
+				return
+			}
+			if !yield(id[0]) {
+				return
+			}
+		}
+	}
+}
+
+func stream(ctx context.Context, bufferSize int, generate func([]int64) (int, error)) <-chan int64 {
+	ids := make(chan int64, max(bufferSize, 0))
+	go func() {
+		defer close(ids)
+		for id := range all(generate) {
+			select {
+			case ids <- id:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return ids
+}
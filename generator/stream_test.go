@@ -0,0 +1,106 @@
+package generator
+
+import (
+	"context"
+	"testing"
+	"time"
+	"uidGenerator/timeprovider/epoch"
+	"uidGenerator/timeprovider/fake"
+)
+
+func TestAll(t *testing.T) {
+	provider := epoch.New(1420070400000)
+	worker := &WorkerVariant{
+		WorkerID:     1,
+		ThreadId:     1,
+		TimeProvider: provider,
+	}
+
+	var ids []int64
+	for id := range worker.All() {
+		ids = append(ids, id)
+		if len(ids) == 2000 {
+			break
+		}
+	}
+
+	for i := 1; i < len(ids); i++ {
+		if ids[i] <= ids[i-1] {
+			t.Fatalf("Expected increasing IDs, got %d after %d", ids[i], ids[i-1])
+		}
+	}
+}
+
+func TestAll_StopsOnError(t *testing.T) {
+	worker := &WorkerVariant{
+		WorkerID:     1,
+		ThreadId:     1,
+		TimeProvider: fake.NewSequence(100, 100, 50),
+	}
+
+	count := 0
+	for range worker.All() {
+		count++
+	}
+	if count != 2 {
+		t.Errorf("Expected 2 IDs before the clock went backwards, got %d", count)
+	}
+}
+
+func TestStream(t *testing.T) {
+	provider := epoch.New(1420070400000)
+	worker := &WorkerVariant{
+		WorkerID:     1,
+		ThreadId:     1,
+		TimeProvider: provider,
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	ids := worker.Stream(ctx, 16)
+
+	var last int64
+	for i := 0; i < 100; i++ {
+		id := <-ids
+		if id <= last {
+			t.Fatalf("Expected increasing IDs, got %d after %d", id, last)
+		}
+		last = id
+	}
+
+	cancel()
+	timeout := time.After(time.Second)
+	for {
+		select {
+		case _, ok := <-ids:
+			if !ok {
+				return
+			}
+		case <-timeout:
+			t.Fatal("Expected stream to be closed after cancel")
+		}
+	}
+}
+
+func TestPool_Stream(t *testing.T) {
+	pool := NewPool(1, epoch.New(1420070400000))
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	idMap := make(map[int64]bool)
+	ids := pool.Stream(ctx, 0)
+	for i := 0; i < 100; i++ {
+		id := <-ids
+		if idMap[id] {
+			t.Errorf("Duplicate ID found: %d", id)
+		}
+		idMap[id] = true
+	}
+
+	count := 0
+	for range pool.All() {
+		count++
+		if count == 10 {
+			break
+		}
+	}
+}
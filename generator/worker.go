@@ -2,6 +2,7 @@ package generator
 
 import (
 	"errors"
+	"fmt"
 	"sync"
 	"time"
 	"uidGenerator/timeprovider"
@@ -16,31 +17,142 @@ var ThreadCap int64 = (1 << ThreadBits) - 1
 var MaxNodeId int64 = (1 << NodeIdBits) - 1
 var MaxCounter int64 = (1 << CounterBitSize) - 1
 
+var ErrClockMovedBackwards = errors.New("invalid previous time stamp")
+var ErrTimeProvider = errors.New("time provider failed")
+var ErrTimeStampOutOfRange = errors.New("time stamp does not fit the layout")
+var ErrBatchTooLarge = fmt.Errorf("batch size exceeds %d", MaxBatch)
+
+// MaxBatch is the largest number of IDs GenerateID allocates for at once,
+// the limit of every listener
+const MaxBatch = 1 << 16
+
+// ClockPolicy Decides what GenerateID does when the clock went backwards
+type ClockPolicy int
+
+const (
+	ClockFail ClockPolicy = iota // Return an error
+	ClockWait                    // Wait up to MaxClockWait for the clock to catch up
+)
+
 type WorkerVariant struct {
-	WorkerID      int64                     // It is the Node ID
-	ThreadId      int64                     // Will be assigned during startup
-	lastTimeStamp int64                     //Used to remember the last time stamp
-	lastCounter   int64                     //Used to remember the last counter value
-	TimeProvider  timeprovider.TimeProvider // Used to get the current time either as epoch or Julian
-	mutex         sync.Mutex               // Ensures thread-safe access to worker state
+	WorkerID             int64                             // It is the Node ID
+	ThreadId             int64                             // Will be assigned during startup
+	lastTimeStamp        int64                             //Used to remember the last time stamp
+	lastCounter          int64                             //Used to remember the last counter value
+	TimeProvider         timeprovider.TimeProvider         // Used to get the current time either as epoch or Julian
+	FallibleTimeProvider timeprovider.FallibleTimeProvider // Used instead of TimeProvider when set, its errors are returned wrapped in ErrTimeProvider
+	Layout               *Layout                           // Bit layout of the IDs, the package bit sizes when nil
+	ClockPolicy          ClockPolicy                       // Reaction to a clock going backwards
+	MaxClockWait         time.Duration                     // Longest wait for the clock with ClockWait, unbounded when 0
+	shard                int                               // Pool shard owning the worker
+	mutex                sync.Mutex                        // Ensures thread-safe access to worker state
 }
 
-// 64 bits UID
+func (w *WorkerVariant) layout() Layout {
+	if w.Layout != nil {
+		return *w.Layout
+	}
+	return DefaultLayout()
+}
+
+// timeStamp Reads the clock, preferring FallibleTimeProvider. Timestamps
+// that would overflow into the other fields of the ID are rejected.
+func (w *WorkerVariant) timeStamp() (int64, error) {
+	var timestamp int64
+	if w.FallibleTimeProvider == nil {
+		timestamp = w.TimeProvider.GetTimeStamp()
+	} else {
+		var err error
+		if timestamp, err = w.FallibleTimeProvider.GetTimeStamp(); err != nil {
+			return 0, fmt.Errorf("%w: %w", ErrTimeProvider, err)
+		}
+	}
+	if timestamp < 0 || timestamp >= 1<<w.layout().TimestampBits {
+		return 0, fmt.Errorf("%w: %d", ErrTimeStampOutOfRange, timestamp)
+	}
+	return timestamp, nil
+}
+
+// currentTimeStamp Reads the clock, applying the clock policy when it is
+// behind the last generated timestamp
+func (w *WorkerVariant) currentTimeStamp() (int64, error) {
+	currentTime, err := w.timeStamp()
+	if err != nil {
+		return 0, err
+	}
+	if currentTime >= w.lastTimeStamp {
+		return currentTime, nil
+	}
+	if w.ClockPolicy != ClockWait {
+		return 0, ErrClockMovedBackwards
+	}
+
+	start := time.Now()
+	for currentTime < w.lastTimeStamp {
+		if w.MaxClockWait > 0 && time.Since(start) > w.MaxClockWait {
+			return 0, ErrClockMovedBackwards
+		}
+		time.Sleep(time.Millisecond)
+		if currentTime, err = w.timeStamp(); err != nil {
+			return 0, err
+		}
+	}
+	return currentTime, nil
+}
+
+// 64 bits UID, at most MaxBatch at a time
 func (w *WorkerVariant) GenerateID(numberOfIds int) ([]int64, error) {
+	if numberOfIds <= 0 {
+		numberOfIds = 1
+	}
+	if numberOfIds > MaxBatch {
+		return nil, ErrBatchTooLarge
+	}
+	ids := make([]int64, numberOfIds)
+	if _, err := w.GenerateInto(ids); err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// GenerateInto Fills dst with IDs without allocating and returns how many
+// were written, which is len(dst) unless an error occurred
+func (w *WorkerVariant) GenerateInto(dst []int64) (int, error) {
+	return w.fill(dst, true, 0)
+}
+
+// GenerateAfter Fills dst like GenerateInto with IDs greater than after.
+// When the thread of the worker would sort before after in its timestamp, it
+// waits for the next timestamp, so IDs of different workers can continue an
+// increasing sequence.
+func (w *WorkerVariant) GenerateAfter(dst []int64, after int64) (int, error) {
+	return w.fill(dst, true, after)
+}
+
+// generateAvailable Fills dst only as far as the counter of the current
+// timestamp allows, instead of waiting for the next timestamp
+func (w *WorkerVariant) generateAvailable(dst []int64) (int, error) {
+	return w.fill(dst, false, 0)
+}
+
+// fill Generates the IDs, all greater than after. IDs are positive, so an
+// after of 0 does not constrain them.
+func (w *WorkerVariant) fill(dst []int64, wait bool, after int64) (int, error) {
 	w.mutex.Lock()
 	defer w.mutex.Unlock()
-	
-	var ids []int64
-	currentTime := w.TimeProvider.GetTimeStamp()
-	if currentTime < w.lastTimeStamp {
-		return nil, errors.New("invalid previous time stamp")
+
+	if len(dst) == 0 {
+		return 0, nil
 	}
-	if numberOfIds <= 0 {
-		numberOfIds = 1
+	layout := w.layout()
+	maxCounter := layout.MaxCounter()
+	currentTime, err := w.currentTimeStamp()
+	if err != nil {
+		return 0, err
 	}
-	
+
 	var counter int64
-	
+
 	// If we're in the same timestamp as last generation, continue from last counter
 	if currentTime == w.lastTimeStamp {
 		counter = w.lastCounter + 1
@@ -48,13 +160,29 @@ func (w *WorkerVariant) GenerateID(numberOfIds int) ([]int64, error) {
 		// New timestamp, reset counter
 		counter = 0
 	}
-	
-	for {
+
+	// Continue only from the timestamp after the one of after
+	if layout.Compose(currentTime, w.WorkerID, w.ThreadId, counter) <= after {
+		currentTime = max(currentTime, layout.Decode(after).Timestamp)
+		counter = maxCounter + 1
+	}
+
+	n := 0
+	for n < len(dst) {
 		// Check if we've exhausted the counter for this timestamp
-		if counter > MaxCounter {
+		if counter > maxCounter {
+			if !wait {
+				break
+			}
 			// Wait for next timestamp
 			for {
-				nextTime := w.TimeProvider.GetTimeStamp()
+				nextTime, err := w.timeStamp()
+				if err != nil {
+					// IDs of the current timestamp are already handed out
+					w.lastTimeStamp = currentTime
+					w.lastCounter = maxCounter
+					return n, err
+				}
 				if nextTime > currentTime {
 					currentTime = nextTime
 					counter = 0
@@ -65,20 +193,13 @@ func (w *WorkerVariant) GenerateID(numberOfIds int) ([]int64, error) {
 				time.Sleep(time.Nanosecond)
 			}
 		}
-		
-		id := currentTime << (NodeIdBits + ThreadBits + CounterBitSize)
-		id |= w.WorkerID << (ThreadBits + CounterBitSize)
-		id |= w.ThreadId << CounterBitSize
-		id |= counter
 
-		ids = append(ids, id)
+		dst[n] = layout.Compose(currentTime, w.WorkerID, w.ThreadId, counter)
 		counter++
-		
-		if len(ids) == numberOfIds {
-			w.lastTimeStamp = currentTime
-			w.lastCounter = counter - 1 // Store the last used counter
-			break
-		}
+		n++
 	}
-	return ids, nil
+
+	w.lastTimeStamp = currentTime
+	w.lastCounter = counter - 1 // Store the last used counter
+	return n, nil
 }
@@ -1,8 +1,12 @@
 package generator
 
 import (
+	"errors"
 	"testing"
+	"time"
 	"uidGenerator/timeprovider/epoch"
+	"uidGenerator/timeprovider/fake"
+	"uidGenerator/timeprovider/julian"
 )
 
 func TestGenerateID_SingleID(t *testing.T) {
@@ -82,6 +86,22 @@ func TestGenerateID_ZeroOrNegativeNumber(t *testing.T) {
 	}
 }
 
+func TestGenerateID_BatchTooLarge(t *testing.T) {
+	worker := &WorkerVariant{
+		WorkerID:     1,
+		ThreadId:     1,
+		TimeProvider: epoch.New(1420070400000),
+	}
+	if _, err := worker.GenerateID(MaxBatch + 1); err != ErrBatchTooLarge {
+		t.Errorf("Expected ErrBatchTooLarge, got %v", err)
+	}
+
+	pool := NewPool(1, epoch.New(1420070400000))
+	if _, err := pool.GenerateID(1e11); err != ErrBatchTooLarge {
+		t.Errorf("Expected ErrBatchTooLarge from the pool, got %v", err)
+	}
+}
+
 func TestGenerateID_IDStructure(t *testing.T) {
 	provider := epoch.New(1420070400000)
 	workerId := int64(5)
@@ -182,3 +202,321 @@ func TestConstants(t *testing.T) {
 		t.Errorf("MaxCounter should be reasonable, got %d", MaxCounter)
 	}
 }
+
+func TestGenerateID_ClockFail(t *testing.T) {
+	worker := &WorkerVariant{
+		WorkerID:     1,
+		ThreadId:     1,
+		TimeProvider: fake.NewSequence(100, 99),
+	}
+
+	if _, err := worker.GenerateID(1); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if _, err := worker.GenerateID(1); err != ErrClockMovedBackwards {
+		t.Errorf("Expected ErrClockMovedBackwards, got %v", err)
+	}
+}
+
+func TestGenerateID_ClockWait(t *testing.T) {
+	worker := &WorkerVariant{
+		WorkerID:     1,
+		ThreadId:     1,
+		TimeProvider: fake.NewSequence(100, 98, 99, 101),
+		ClockPolicy:  ClockWait,
+	}
+
+	first, err := worker.GenerateID(1)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	second, err := worker.GenerateID(1)
+	if err != nil {
+		t.Fatalf("Expected worker to wait for the clock, got %v", err)
+	}
+	if Decode(second[0]).Timestamp != 101 || second[0] <= first[0] {
+		t.Errorf("Expected ID from timestamp 101 after %d, got %d", first[0], second[0])
+	}
+}
+
+func TestGenerateID_ClockWaitLimit(t *testing.T) {
+	worker := &WorkerVariant{
+		WorkerID:     1,
+		ThreadId:     1,
+		TimeProvider: fake.NewSequence(100, 50),
+		ClockPolicy:  ClockWait,
+		MaxClockWait: 5 * time.Millisecond,
+	}
+
+	if _, err := worker.GenerateID(1); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if _, err := worker.GenerateID(1); err != ErrClockMovedBackwards {
+		t.Errorf("Expected ErrClockMovedBackwards after waiting, got %v", err)
+	}
+}
+
+func TestGenerateID_CustomLayout(t *testing.T) {
+	layout := Layout{TimestampBits: 39, WorkerBits: 16, ThreadBits: 1, CounterBits: 7}
+	worker := &WorkerVariant{
+		WorkerID:     40000,
+		ThreadId:     1,
+		TimeProvider: fake.NewSequence(100, 100, 101),
+		Layout:       &layout,
+	}
+
+	// The counter holds 128 values, so a batch of 130 spills into the next timestamp
+	ids, err := worker.GenerateID(130)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	last := layout.Decode(ids[len(ids)-1])
+	expected := Components{Timestamp: 101, WorkerID: 40000, ThreadId: 1, Counter: 1}
+	if last != expected {
+		t.Errorf("Expected %+v, got %+v", expected, last)
+	}
+}
+
+func TestGenerateInto(t *testing.T) {
+	provider := epoch.New(1420070400000)
+	worker := &WorkerVariant{
+		WorkerID:     1,
+		ThreadId:     1,
+		TimeProvider: provider,
+	}
+
+	dst := make([]int64, 50)
+	n, err := worker.GenerateInto(dst)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if n != len(dst) {
+		t.Errorf("Expected %d IDs, got %d", len(dst), n)
+	}
+	for i := 1; i < len(dst); i++ {
+		if dst[i] <= dst[i-1] {
+			t.Errorf("Expected increasing IDs, got %d after %d", dst[i], dst[i-1])
+		}
+	}
+
+	// IDs continue after the previous batch
+	next, err := worker.GenerateID(1)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if next[0] <= dst[len(dst)-1] {
+		t.Errorf("Expected %d to follow %d", next[0], dst[len(dst)-1])
+	}
+
+	if n, err := worker.GenerateInto(nil); n != 0 || err != nil {
+		t.Errorf("Expected nothing for an empty buffer, got %d and %v", n, err)
+	}
+}
+
+func TestGenerateInto_ZeroAllocs(t *testing.T) {
+	provider := epoch.New(1420070400000)
+	worker := &WorkerVariant{
+		WorkerID:     1,
+		ThreadId:     1,
+		TimeProvider: provider,
+	}
+	dst := make([]int64, 100)
+
+	allocs := testing.AllocsPerRun(100, func() {
+		if _, err := worker.GenerateInto(dst); err != nil {
+			t.Errorf("Expected no error, got %v", err)
+		}
+	})
+	if allocs != 0 {
+		t.Errorf("Expected 0 allocations, got %v", allocs)
+	}
+}
+
+func TestGenerateID_CounterRollover(t *testing.T) {
+	clock := fake.NewSequence(100, 100, 100, 101)
+	worker := &WorkerVariant{
+		WorkerID:     1,
+		ThreadId:     1,
+		TimeProvider: clock,
+	}
+
+	// Fills the counter of timestamp 100 up to its last value
+	if _, err := worker.GenerateID(int(MaxCounter)); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	ids, err := worker.GenerateID(2)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	expected := []Components{
+		{Timestamp: 100, WorkerID: 1, ThreadId: 1, Counter: MaxCounter},
+		{Timestamp: 101, WorkerID: 1, ThreadId: 1, Counter: 0},
+	}
+	for i, id := range ids {
+		if actual := Decode(id); actual != expected[i] {
+			t.Errorf("Expected %+v, got %+v", expected[i], actual)
+		}
+	}
+	if calls := clock.Calls(); calls != 4 {
+		t.Errorf("Expected the worker to read the clock 4 times, got %d", calls)
+	}
+}
+
+func TestGenerateID_TimeStampOutOfRange(t *testing.T) {
+	for _, timestamp := range []int64{-1, 1 << EpochBits, 2e14} {
+		worker := &WorkerVariant{
+			WorkerID:     1,
+			ThreadId:     1,
+			TimeProvider: fake.NewClock(timestamp),
+		}
+		if _, err := worker.GenerateID(1); !errors.Is(err, ErrTimeStampOutOfRange) {
+			t.Errorf("Expected ErrTimeStampOutOfRange for %d, got %v", timestamp, err)
+		}
+	}
+}
+
+func TestGenerateAfter(t *testing.T) {
+	after := DefaultLayout().Compose(100, 1, 2, 5)
+	testCases := []struct {
+		threadId int64
+		expected Components
+	}{
+		// Sorts after the thread of after within its timestamp
+		{3, Components{Timestamp: 100, WorkerID: 1, ThreadId: 3, Counter: 0}},
+		// Sorts before it, so waits for the next timestamp
+		{1, Components{Timestamp: 101, WorkerID: 1, ThreadId: 1, Counter: 0}},
+	}
+
+	for _, tc := range testCases {
+		worker := &WorkerVariant{
+			WorkerID:     1,
+			ThreadId:     tc.threadId,
+			TimeProvider: fake.NewSequence(100, 100, 101),
+		}
+		ids := make([]int64, 1)
+		if _, err := worker.GenerateAfter(ids, after); err != nil {
+			t.Fatalf("Expected no error, got %v", err)
+		}
+		if actual := Decode(ids[0]); actual != tc.expected {
+			t.Errorf("Expected %+v, got %+v", tc.expected, actual)
+		}
+	}
+}
+
+func TestGenerateID_SkewedClockStepsBack(t *testing.T) {
+	base := fake.NewClock(1000)
+	clock := fake.NewSkewed(base, 0, 0)
+	worker := &WorkerVariant{
+		WorkerID:     1,
+		ThreadId:     1,
+		TimeProvider: clock,
+	}
+
+	if _, err := worker.GenerateID(1); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// An NTP step of 10 units back
+	base.Advance(5)
+	clock.SetOffset(-10)
+	if _, err := worker.GenerateID(1); err != ErrClockMovedBackwards {
+		t.Errorf("Expected ErrClockMovedBackwards, got %v", err)
+	}
+
+	// Generation resumes once the clock has caught up
+	base.Advance(5)
+	ids, err := worker.GenerateID(1)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if timestamp := Decode(ids[0]).Timestamp; timestamp != 1000 {
+		t.Errorf("Expected timestamp 1000, got %d", timestamp)
+	}
+}
+
+func TestGenerateID_TimeProviderError(t *testing.T) {
+	clock := fake.NewFallible(fake.NewClock(100))
+	worker := &WorkerVariant{
+		WorkerID:             1,
+		ThreadId:             1,
+		FallibleTimeProvider: clock,
+	}
+
+	broken := errors.New("time source offline")
+	clock.SetError(broken)
+	ids, err := worker.GenerateID(1)
+	if !errors.Is(err, ErrTimeProvider) || !errors.Is(err, broken) {
+		t.Errorf("Expected ErrTimeProvider wrapping the provider error, got %v", err)
+	}
+	if ids != nil {
+		t.Errorf("Expected no IDs, got %v", ids)
+	}
+
+	clock.SetError(nil)
+	if _, err := worker.GenerateID(1); err != nil {
+		t.Errorf("Expected no error once the provider recovers, got %v", err)
+	}
+}
+
+// failingAfter Returns timestamp for the given number of reads and fails
+// afterwards
+type failingAfter struct {
+	timestamp int64
+	reads     int
+	err       error
+}
+
+func (p *failingAfter) GetTimeStamp() (int64, error) {
+	if p.reads == 0 {
+		return 0, p.err
+	}
+	p.reads--
+	return p.timestamp, nil
+}
+
+func TestGenerateInto_TimeProviderErrorOnRollover(t *testing.T) {
+	broken := errors.New("time source offline")
+	clock := &failingAfter{timestamp: 100, reads: 3, err: broken}
+	worker := &WorkerVariant{
+		WorkerID:             1,
+		ThreadId:             1,
+		FallibleTimeProvider: clock,
+	}
+
+	// Leaves one counter value of timestamp 100
+	if _, err := worker.GenerateID(int(MaxCounter)); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// The counter is exhausted and the clock fails while waiting for the
+	// next timestamp, so the batch stops short
+	dst := make([]int64, 2)
+	n, err := worker.GenerateInto(dst)
+	if !errors.Is(err, ErrTimeProvider) || !errors.Is(err, broken) {
+		t.Errorf("Expected ErrTimeProvider wrapping the provider error, got %v", err)
+	}
+	if n != 1 || Decode(dst[0]).Counter != MaxCounter {
+		t.Errorf("Expected the last ID of timestamp 100, got %d IDs", n)
+	}
+
+	// The exhausted timestamp is not reused
+	clock.timestamp, clock.reads = 100, 1
+	if _, err := worker.GenerateInto(dst[:1]); !errors.Is(err, ErrTimeProvider) {
+		t.Errorf("Expected to wait for the next timestamp and fail, got %v", err)
+	}
+}
+
+func TestNewPool_AdaptsCheckedProviders(t *testing.T) {
+	provider := julian.New(0)
+	pool := NewPool(1, provider)
+	worker := pool.Acquire()
+	defer pool.Release(worker)
+
+	if worker.FallibleTimeProvider == nil {
+		t.Fatal("Expected the pool to adapt the provider")
+	}
+	if _, err := worker.GenerateID(1); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"uidGenerator/codec"
 	"uidGenerator/generator"
 	"uidGenerator/timeprovider/epoch"
 
@@ -172,3 +173,48 @@ func TestGenerator_ResponseFormat(t *testing.T) {
 		t.Error("Response should contain 'ids' field")
 	}
 }
+
+func TestGenerator_BinaryFormats(t *testing.T) {
+	testCases := []struct {
+		accept      string
+		contentType string
+		decode      func([]byte) ([]int64, error)
+	}{
+		{"application/octet-stream", codec.MIMEOctetStream, codec.UnmarshalFixed},
+		{"application/x-protobuf, application/json;q=0.5", codec.MIMEProtobuf, codec.UnmarshalDeltaVarint},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.contentType, func(t *testing.T) {
+			e := echo.New()
+			req := httptest.NewRequest(http.MethodGet, "/?numberOfIds=5", nil)
+			req.Header.Set(echo.HeaderAccept, tc.accept)
+			rec := httptest.NewRecorder()
+			c := e.NewContext(req, rec)
+
+			provider := epoch.New(1420070400000)
+			worker := &generator.WorkerVariant{
+				WorkerID:     1,
+				ThreadId:     1,
+				TimeProvider: provider,
+			}
+			c.Set("worker", worker)
+
+			if err := Generator(c); err != nil {
+				t.Errorf("Expected no error, got %v", err)
+			}
+
+			if contentType := rec.Header().Get("Content-Type"); contentType != tc.contentType {
+				t.Errorf("Expected content type %s, got %s", tc.contentType, contentType)
+			}
+
+			ids, err := tc.decode(rec.Body.Bytes())
+			if err != nil {
+				t.Errorf("Failed to decode response: %v", err)
+			}
+			if len(ids) != 5 {
+				t.Errorf("Expected 5 IDs, got %d", len(ids))
+			}
+		})
+	}
+}
@@ -0,0 +1,16 @@
+package handler
+
+import (
+	"github.com/labstack/echo/v4"
+	"uidGenerator/generator"
+	"uidGenerator/httpapi"
+)
+
+// WebSocket Adapts httpapi.ServeWebSocket to Echo. Sessions take a worker of
+// pool for every batch instead of holding one.
+func WebSocket(pool *generator.Pool) echo.HandlerFunc {
+	return func(c echo.Context) error {
+		httpapi.ServeWebSocket(c.Response(), c.Request(), pool)
+		return nil
+	}
+}
@@ -0,0 +1,196 @@
+package handler
+
+import (
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+	"uidGenerator/generator"
+	"uidGenerator/timeprovider/epoch"
+
+	"github.com/labstack/echo/v4"
+	"golang.org/x/net/websocket"
+)
+
+type wsResponse struct {
+	Ids   []int64 `json:"ids"`
+	Error string  `json:"error"`
+}
+
+func newWebSocketServer(t *testing.T) *websocket.Conn {
+	return dialWebSocket(t, newPoolServer(t, generator.NewPool(1, epoch.New(1420070400000))))
+}
+
+func newPoolServer(t *testing.T, pool *generator.Pool) *httptest.Server {
+	e := echo.New()
+	e.GET("/ws", WebSocket(pool))
+
+	server := httptest.NewServer(e)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func dialWebSocket(t *testing.T, server *httptest.Server) *websocket.Conn {
+	url := "ws" + strings.TrimPrefix(server.URL, "http") + "/ws"
+	ws, err := websocket.Dial(url, "", server.URL)
+	if err != nil {
+		t.Fatalf("Failed to dial websocket: %v", err)
+	}
+	t.Cleanup(func() { ws.Close() })
+	return ws
+}
+
+func TestWebSocket_Batches(t *testing.T) {
+	ws := newWebSocketServer(t)
+
+	var last int64
+	for i := 0; i < 5; i++ {
+		if err := websocket.JSON.Send(ws, map[string]int{"count": 10}); err != nil {
+			t.Fatalf("Failed to send request: %v", err)
+		}
+
+		var response wsResponse
+		if err := websocket.JSON.Receive(ws, &response); err != nil {
+			t.Fatalf("Failed to receive response: %v", err)
+		}
+
+		if len(response.Ids) != 10 {
+			t.Errorf("Expected 10 IDs, got %d", len(response.Ids))
+		}
+
+		// IDs on one session must be strictly increasing across batches
+		for _, id := range response.Ids {
+			if id <= last {
+				t.Errorf("Expected ID %d to be greater than %d", id, last)
+			}
+			last = id
+		}
+	}
+}
+
+func TestWebSocket_DefaultCount(t *testing.T) {
+	ws := newWebSocketServer(t)
+
+	if err := websocket.Message.Send(ws, "{}"); err != nil {
+		t.Fatalf("Failed to send request: %v", err)
+	}
+
+	var response wsResponse
+	if err := websocket.JSON.Receive(ws, &response); err != nil {
+		t.Fatalf("Failed to receive response: %v", err)
+	}
+
+	if len(response.Ids) != 1 {
+		t.Errorf("Expected 1 ID (default), got %d", len(response.Ids))
+	}
+}
+
+func TestWebSocket_InvalidFrame(t *testing.T) {
+	ws := newWebSocketServer(t)
+
+	if err := websocket.Message.Send(ws, "not json"); err != nil {
+		t.Fatalf("Failed to send request: %v", err)
+	}
+
+	var response wsResponse
+	if err := websocket.JSON.Receive(ws, &response); err != nil {
+		t.Fatalf("Failed to receive response: %v", err)
+	}
+
+	if response.Error == "" {
+		t.Error("Expected error for invalid frame")
+	}
+
+	// The session should survive a bad frame
+	if err := websocket.JSON.Send(ws, map[string]int{"count": 2}); err != nil {
+		t.Fatalf("Failed to send request: %v", err)
+	}
+	response = wsResponse{}
+	if err := websocket.JSON.Receive(ws, &response); err != nil {
+		t.Fatalf("Failed to receive response: %v", err)
+	}
+	if len(response.Ids) != 2 {
+		t.Errorf("Expected 2 IDs, got %d", len(response.Ids))
+	}
+}
+
+func TestWebSocket_BatchTooLarge(t *testing.T) {
+	ws := newWebSocketServer(t)
+
+	if err := websocket.Message.Send(ws, `{"count": 100000000000}`); err != nil {
+		t.Fatalf("Failed to send request: %v", err)
+	}
+	var response wsResponse
+	if err := websocket.JSON.Receive(ws, &response); err != nil {
+		t.Fatalf("Failed to receive response: %v", err)
+	}
+	if response.Error != generator.ErrBatchTooLarge.Error() || len(response.Ids) != 0 {
+		t.Errorf("Expected %q, got %+v", generator.ErrBatchTooLarge, response)
+	}
+
+	// The session survives the rejected frame
+	if err := websocket.JSON.Send(ws, map[string]int{"count": 2}); err != nil {
+		t.Fatalf("Failed to send request: %v", err)
+	}
+	response = wsResponse{}
+	if err := websocket.JSON.Receive(ws, &response); err != nil {
+		t.Fatalf("Failed to receive response: %v", err)
+	}
+	if len(response.Ids) != 2 {
+		t.Errorf("Expected 2 IDs, got %d", len(response.Ids))
+	}
+}
+
+func TestWebSocket_IdleSessionsHoldNoWorker(t *testing.T) {
+	provider := epoch.New(1420070400000)
+	pool := generator.NewPoolOf([]*generator.WorkerVariant{
+		{WorkerID: 1, ThreadId: 1, TimeProvider: provider},
+		{WorkerID: 1, ThreadId: 2, TimeProvider: provider},
+	})
+	server := newPoolServer(t, pool)
+
+	// More sessions than workers, each with a batch behind it
+	var sessions []*websocket.Conn
+	var last []int64
+	for i := 0; i < 4; i++ {
+		ws := dialWebSocket(t, server)
+		if err := websocket.JSON.Send(ws, map[string]int{"count": 5}); err != nil {
+			t.Fatalf("Failed to send request: %v", err)
+		}
+		var response wsResponse
+		if err := websocket.JSON.Receive(ws, &response); err != nil {
+			t.Fatalf("Failed to receive response: %v", err)
+		}
+		sessions = append(sessions, ws)
+		last = append(last, response.Ids[len(response.Ids)-1])
+	}
+
+	// The pool still serves other callers
+	done := make(chan error, 1)
+	go func() {
+		_, err := pool.GenerateID(1)
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("Expected no error, got %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected idle sessions not to hold workers")
+	}
+
+	// Every session keeps increasing whichever worker serves it
+	for i, ws := range sessions {
+		if err := websocket.JSON.Send(ws, map[string]int{"count": 5}); err != nil {
+			t.Fatalf("Failed to send request: %v", err)
+		}
+		var response wsResponse
+		if err := websocket.JSON.Receive(ws, &response); err != nil {
+			t.Fatalf("Failed to receive response: %v", err)
+		}
+		if response.Ids[0] <= last[i] {
+			t.Errorf("Expected ID %d of session %d to be greater than %d", response.Ids[0], i, last[i])
+		}
+	}
+}
@@ -0,0 +1,62 @@
+package httpapi
+
+import (
+	"net/http"
+	"strconv"
+)
+
+// ClockHeader Carries hybrid logical clock timestamps between nodes
+const ClockHeader = "X-Clock-Timestamp"
+
+// Clock Is a time provider that merges the timestamps of peers, such as the
+// hlc provider
+type Clock interface {
+	Observe(timestamp int64) error
+	Last() int64
+}
+
+// ObserveClock Merges the ClockHeader of a request into clock before next
+// generates IDs for it, and sets the header of the response to the latest
+// timestamp of clock, so the caller can merge it in turn. Requests with an
+// invalid or rejected header are answered with 400.
+func ObserveClock(clock Clock, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if header := r.Header.Get(ClockHeader); header != "" {
+			timestamp, err := strconv.ParseInt(header, 10, 64)
+			if err == nil {
+				err = clock.Observe(timestamp)
+			}
+			if err != nil {
+				writeJSON(w, http.StatusBadRequest, map[string]interface{}{
+					"error": err.Error(),
+				})
+				return
+			}
+		}
+
+		next.ServeHTTP(&clockWriter{ResponseWriter: w, clock: clock}, r)
+	})
+}
+
+// clockWriter Sets the ClockHeader once the response is complete but before
+// its headers are sent
+type clockWriter struct {
+	http.ResponseWriter
+	clock       Clock
+	wroteHeader bool
+}
+
+func (w *clockWriter) WriteHeader(code int) {
+	if !w.wroteHeader {
+		w.wroteHeader = true
+		w.Header().Set(ClockHeader, strconv.FormatInt(w.clock.Last(), 10))
+	}
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *clockWriter) Write(b []byte) (int, error) {
+	if !w.wroteHeader {
+		w.WriteHeader(http.StatusOK)
+	}
+	return w.ResponseWriter.Write(b)
+}
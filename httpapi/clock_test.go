@@ -0,0 +1,111 @@
+package httpapi
+
+import (
+	"context"
+	"encoding/json"
+	"math"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+	"uidGenerator/generator"
+	"uidGenerator/timeprovider/hlc"
+)
+
+func TestObserveClock(t *testing.T) {
+	clock := hlc.New(1704067200000)
+	handler := ObserveClock(clock, Handler(generator.NewPool(1, clock)))
+
+	// A peer half a second ahead
+	peer := clock.GetTimeStamp() + 500<<hlc.DefaultLogicalBits
+	req := httptest.NewRequest(http.MethodGet, "/?numberOfIds=3", nil)
+	req.Header.Set(ClockHeader, strconv.FormatInt(peer, 10))
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", rec.Code)
+	}
+	var response struct {
+		Ids []int64 `json:"ids"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&response); err != nil {
+		t.Fatalf("Expected JSON body, got %v", err)
+	}
+
+	layout := generator.DefaultLayout()
+	for _, id := range response.Ids {
+		if timestamp := layout.Decode(id).Timestamp; timestamp <= peer {
+			t.Errorf("Expected timestamp of %d to be after the peer's %d, got %d", id, peer, timestamp)
+		}
+	}
+
+	last, err := strconv.ParseInt(rec.Header().Get(ClockHeader), 10, 64)
+	if err != nil {
+		t.Fatalf("Expected a numeric %s header, got %q", ClockHeader, rec.Header().Get(ClockHeader))
+	}
+	if timestamp := layout.Decode(response.Ids[len(response.Ids)-1]).Timestamp; last < timestamp {
+		t.Errorf("Expected header %d to cover the returned timestamp %d", last, timestamp)
+	}
+}
+
+func TestObserveClock_BypassesCache(t *testing.T) {
+	clock := hlc.New(1704067200000)
+	pool := generator.NewPool(1, clock)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	cache := pool.EnableCache(ctx, generator.CacheConfig{LowWatermark: 8, HighWatermark: 16})
+	for deadline := time.Now().Add(time.Second); cache.Stats().Size < 16; {
+		if time.Now().After(deadline) {
+			t.Fatal("Expected the cache to fill")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	handler := ObserveClock(clock, Handler(pool))
+
+	// The cached IDs predate a peer half a second ahead
+	peer := clock.GetTimeStamp() + 500<<hlc.DefaultLogicalBits
+	req := httptest.NewRequest(http.MethodGet, "/?numberOfIds=4", nil)
+	req.Header.Set(ClockHeader, strconv.FormatInt(peer, 10))
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	var response struct {
+		Ids []int64 `json:"ids"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&response); err != nil {
+		t.Fatalf("Expected JSON body, got %v", err)
+	}
+	layout := generator.DefaultLayout()
+	for _, id := range response.Ids {
+		if timestamp := layout.Decode(id).Timestamp; timestamp <= peer {
+			t.Errorf("Expected timestamp of %d to be after the peer's %d, got %d", id, peer, timestamp)
+		}
+	}
+	if hits := cache.Stats().Hits; hits != 0 {
+		t.Errorf("Expected no cache hits, got %d", hits)
+	}
+}
+
+func TestObserveClock_InvalidHeader(t *testing.T) {
+	clock := hlc.New(1420070400000, hlc.WithMaxDrift(time.Second), hlc.WithTimeStampBits(generator.EpochBits))
+	handler := ObserveClock(clock, Handler(generator.NewPool(1, clock)))
+
+	for _, header := range []string{
+		"abc",
+		strconv.FormatInt(clock.GetTimeStamp()+1<<20, 10),
+		"-1",
+		strconv.FormatInt(1<<generator.EpochBits, 10),
+		strconv.FormatInt(math.MaxInt64, 10),
+	} {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set(ClockHeader, header)
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("Expected status 400 for %q, got %d", header, rec.Code)
+		}
+	}
+}
@@ -0,0 +1,39 @@
+package httpapi
+
+import (
+	"errors"
+	"net/http"
+	"uidGenerator/generator"
+)
+
+// errorStatuses Maps generation errors to HTTP statuses, any other error is
+// answered with 500. Clock errors are temporary, so callers may retry;
+// oversized batches are the caller's fault.
+var errorStatuses = []struct {
+	err    error
+	status int
+}{
+	{generator.ErrClockMovedBackwards, http.StatusServiceUnavailable},
+	{generator.ErrTimeProvider, http.StatusServiceUnavailable},
+	{generator.ErrBatchTooLarge, http.StatusBadRequest},
+}
+
+func errorStatus(err error) int {
+	for _, mapping := range errorStatuses {
+		if errors.Is(err, mapping.err) {
+			return mapping.status
+		}
+	}
+	return http.StatusInternalServerError
+}
+
+// writeError Answers with the status of err and its message as JSON
+func writeError(w http.ResponseWriter, err error) {
+	status := errorStatus(err)
+	if status == http.StatusServiceUnavailable {
+		w.Header().Set("Retry-After", "1")
+	}
+	writeJSON(w, status, map[string]interface{}{
+		"error": err.Error(),
+	})
+}
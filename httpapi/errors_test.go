@@ -0,0 +1,60 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"uidGenerator/generator"
+)
+
+var errSourceOffline = errors.New("time source offline")
+
+// failingProvider Is a clock whose reading always fails
+type failingProvider struct{}
+
+func (failingProvider) GetTimeStamp() (int64, error) {
+	return 0, errSourceOffline
+}
+
+func TestHandler_TimeProviderError(t *testing.T) {
+	worker := &generator.WorkerVariant{WorkerID: 1, ThreadId: 1, FallibleTimeProvider: failingProvider{}}
+	handler := Handler(generator.NewPoolOf([]*generator.WorkerVariant{worker}))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+
+	if rec.Code != http.StatusServiceUnavailable {
+		t.Errorf("Expected status 503, got %d", rec.Code)
+	}
+	if retry := rec.Header().Get("Retry-After"); retry != "1" {
+		t.Errorf("Expected Retry-After 1, got %q", retry)
+	}
+	var response map[string]string
+	if err := json.NewDecoder(rec.Body).Decode(&response); err != nil {
+		t.Fatalf("Expected JSON body, got %v", err)
+	}
+	if expected := "time provider failed: time source offline"; response["error"] != expected {
+		t.Errorf("Expected error %q, got %q", expected, response["error"])
+	}
+}
+
+func TestErrorStatus(t *testing.T) {
+	testCases := []struct {
+		err      error
+		expected int
+	}{
+		{generator.ErrClockMovedBackwards, http.StatusServiceUnavailable},
+		{fmt.Errorf("%w: %w", generator.ErrTimeProvider, errSourceOffline), http.StatusServiceUnavailable},
+		{generator.ErrBatchTooLarge, http.StatusBadRequest},
+		{errors.New("unexpected"), http.StatusInternalServerError},
+	}
+
+	for _, tc := range testCases {
+		if actual := errorStatus(tc.err); actual != tc.expected {
+			t.Errorf("Expected %d for %v, got %d", tc.expected, tc.err, actual)
+		}
+	}
+}
@@ -0,0 +1,123 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"sync"
+	"uidGenerator/codec"
+	"uidGenerator/generator"
+)
+
+// maxPooledIds bounds the buffers kept for reuse, larger batches are left to
+// the garbage collector
+const maxPooledIds = 1 << 12
+
+var idBuffers = sync.Pool{
+	New: func() interface{} {
+		buffer := make([]int64, 0, 64)
+		return &buffer
+	},
+}
+
+// Handler Serves ID requests with the workers of pool. Requests carrying the
+// ClockHeader bypass the cache of the pool. It only depends on net/http, so
+// it can be mounted on any router:
+//
+//	mux.Handle("/ids", httpapi.Handler(pool))
+func Handler(pool *generator.Pool) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			w.Header().Set("Allow", "GET, HEAD")
+			writeJSON(w, http.StatusMethodNotAllowed, map[string]interface{}{
+				"error": http.StatusText(http.StatusMethodNotAllowed),
+			})
+			return
+		}
+
+		if r.Header.Get(ClockHeader) != "" {
+			// Cached IDs may predate the timestamp ObserveClock merged, fresh
+			// ones are ordered after it
+			generate(w, r, func(dst []int64) (int, error) {
+				return pool.GenerateAfter(dst, 0)
+			})
+			return
+		}
+		generate(w, r, pool.GenerateInto)
+	})
+}
+
+// Generate Answers one ID request using worker. The numberOfIds query
+// parameter selects the batch size and the Accept header the encoding.
+func Generate(w http.ResponseWriter, r *http.Request, worker *generator.WorkerVariant) {
+	generate(w, r, worker.GenerateInto)
+}
+
+// CacheStatsHandler Reports the hit rate of a pool cache
+func CacheStatsHandler(cache *generator.Cache) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		stats := cache.Stats()
+		writeJSON(w, http.StatusOK, map[string]interface{}{
+			"hits":    stats.Hits,
+			"misses":  stats.Misses,
+			"size":    stats.Size,
+			"hitRate": stats.HitRate(),
+		})
+	})
+}
+
+func generate(w http.ResponseWriter, r *http.Request, generateInto func([]int64) (int, error)) {
+	idn := r.URL.Query().Get("numberOfIds")
+	numberOfIds := 1
+	if idn != "" {
+		v, err := strconv.Atoi(idn)
+		if err == nil {
+			numberOfIds = v
+		}
+	}
+
+	if numberOfIds <= 0 {
+		numberOfIds = 1
+	}
+	if numberOfIds > generator.MaxBatch {
+		writeError(w, generator.ErrBatchTooLarge)
+		return
+	}
+
+	buffer := idBuffers.Get().(*[]int64)
+	if cap(*buffer) < numberOfIds {
+		*buffer = make([]int64, numberOfIds)
+	}
+	defer func() {
+		if cap(*buffer) <= maxPooledIds {
+			idBuffers.Put(buffer)
+		}
+	}()
+	ids := (*buffer)[:numberOfIds]
+
+	_, err := generateInto(ids)
+
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	// JSON is offered too, so a client listing it before a binary format gets
+	// JSON
+	if format, ok := codec.Lookup(negotiate(r.Header.Get("Accept"), append(codec.MediaTypes(), "application/json")...)); ok {
+		w.Header().Set("Content-Type", format.MIME)
+		w.WriteHeader(http.StatusOK)
+		w.Write(format.Marshal(ids))
+		return
+	}
+
+	writeJSON(w, http.StatusOK, map[string]interface{}{
+		"ids": ids,
+	})
+}
+
+func writeJSON(w http.ResponseWriter, code int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(body)
+}
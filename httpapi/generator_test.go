@@ -0,0 +1,150 @@
+package httpapi
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"uidGenerator/codec"
+	"uidGenerator/generator"
+	"uidGenerator/timeprovider/epoch"
+
+	"golang.org/x/net/websocket"
+)
+
+func newMux() *http.ServeMux {
+	pool := generator.NewPool(1, epoch.New(1420070400000))
+	mux := http.NewServeMux()
+	mux.Handle("/ids", Handler(pool))
+	mux.Handle("/ids/ws", WebSocketHandler(pool))
+	return mux
+}
+
+func TestHandler_ServeMux(t *testing.T) {
+	mux := newMux()
+	req := httptest.NewRequest(http.MethodGet, "/ids?numberOfIds=4", nil)
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Errorf("Expected status 200, got %d", rec.Code)
+	}
+	if contentType := rec.Header().Get("Content-Type"); !strings.Contains(contentType, "application/json") {
+		t.Errorf("Expected JSON content type, got %s", contentType)
+	}
+
+	var response struct {
+		Ids []int64 `json:"ids"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to parse response: %v", err)
+	}
+	if len(response.Ids) != 4 {
+		t.Errorf("Expected 4 IDs, got %d", len(response.Ids))
+	}
+}
+
+func TestHandler_Negotiation(t *testing.T) {
+	mux := newMux()
+	for _, format := range codec.Formats {
+		t.Run(format.MIME, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/ids?numberOfIds=3", nil)
+			req.Header.Set("Accept", format.MIME)
+			rec := httptest.NewRecorder()
+			mux.ServeHTTP(rec, req)
+
+			if contentType := rec.Header().Get("Content-Type"); contentType != format.MIME {
+				t.Errorf("Expected content type %s, got %s", format.MIME, contentType)
+			}
+			ids, err := format.Unmarshal(rec.Body.Bytes())
+			if err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+			if len(ids) != 3 {
+				t.Errorf("Expected 3 IDs, got %d", len(ids))
+			}
+		})
+	}
+}
+
+func TestHandler_BatchTooLarge(t *testing.T) {
+	mux := newMux()
+	for _, numberOfIds := range []string{"65537", "100000000000"} {
+		req := httptest.NewRequest(http.MethodGet, "/ids?numberOfIds="+numberOfIds, nil)
+		rec := httptest.NewRecorder()
+		mux.ServeHTTP(rec, req)
+
+		if rec.Code != http.StatusBadRequest {
+			t.Errorf("Expected status 400 for %s IDs, got %d", numberOfIds, rec.Code)
+		}
+	}
+}
+
+func TestHandler_MethodNotAllowed(t *testing.T) {
+	mux := newMux()
+	req := httptest.NewRequest(http.MethodPost, "/ids", nil)
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusMethodNotAllowed {
+		t.Errorf("Expected status 405, got %d", rec.Code)
+	}
+	if allow := rec.Header().Get("Allow"); allow != "GET, HEAD" {
+		t.Errorf("Expected Allow header, got %q", allow)
+	}
+}
+
+func TestWebSocketHandler(t *testing.T) {
+	server := httptest.NewServer(newMux())
+	defer server.Close()
+
+	ws, err := websocket.Dial("ws"+strings.TrimPrefix(server.URL, "http")+"/ids/ws", "", server.URL)
+	if err != nil {
+		t.Fatalf("Failed to dial websocket: %v", err)
+	}
+	defer ws.Close()
+
+	if err := websocket.JSON.Send(ws, map[string]int{"count": 3}); err != nil {
+		t.Fatalf("Failed to send request: %v", err)
+	}
+	var response struct {
+		Ids []int64 `json:"ids"`
+	}
+	if err := websocket.JSON.Receive(ws, &response); err != nil {
+		t.Fatalf("Failed to receive response: %v", err)
+	}
+	if len(response.Ids) != 3 {
+		t.Errorf("Expected 3 IDs, got %d", len(response.Ids))
+	}
+}
+
+func TestCacheStatsHandler(t *testing.T) {
+	pool := generator.NewPool(1, epoch.New(1420070400000))
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	cache := pool.EnableCache(ctx, generator.CacheConfig{LowWatermark: 5, HighWatermark: 10})
+
+	mux := http.NewServeMux()
+	mux.Handle("/ids", Handler(pool))
+	mux.Handle("/cache", CacheStatsHandler(cache))
+
+	rec := httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/ids?numberOfIds=3", nil))
+
+	rec = httptest.NewRecorder()
+	mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/cache", nil))
+
+	var stats struct {
+		Hits    int64   `json:"hits"`
+		Misses  int64   `json:"misses"`
+		HitRate float64 `json:"hitRate"`
+	}
+	if err := json.Unmarshal(rec.Body.Bytes(), &stats); err != nil {
+		t.Fatalf("Failed to parse response: %v", err)
+	}
+	if stats.Hits+stats.Misses != 3 {
+		t.Errorf("Expected 3 IDs to be counted, got %+v", stats)
+	}
+}
@@ -0,0 +1,38 @@
+package httpapi
+
+import (
+	"strconv"
+	"strings"
+)
+
+// negotiate Returns the first media type listed in the Accept header that is
+// also one of the offers, or an empty string when none matches. Types with a
+// quality of 0 are not acceptable, other quality values are ignored, clients
+// are expected to list the preferred type first.
+func negotiate(accept string, offers ...string) string {
+	for _, mediaRange := range strings.Split(accept, ",") {
+		mediaType, params, _ := strings.Cut(mediaRange, ";")
+		mediaType = strings.TrimSpace(mediaType)
+		if rejected(params) {
+			continue
+		}
+		for _, offer := range offers {
+			if strings.EqualFold(mediaType, offer) {
+				return offer
+			}
+		}
+	}
+	return ""
+}
+
+// rejected Reports whether the parameters of a media range hold q=0
+func rejected(params string) bool {
+	for _, param := range strings.Split(params, ";") {
+		name, value, _ := strings.Cut(param, "=")
+		if strings.EqualFold(strings.TrimSpace(name), "q") {
+			q, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+			return err == nil && q == 0
+		}
+	}
+	return false
+}
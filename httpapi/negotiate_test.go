@@ -0,0 +1,32 @@
+package httpapi
+
+import "testing"
+
+func TestNegotiate(t *testing.T) {
+	offers := []string{"application/octet-stream", "application/cbor", "application/json"}
+
+	testCases := []struct {
+		accept   string
+		expected string
+	}{
+		{"", ""},
+		{"*/*", ""},
+		{"application/json", "application/json"},
+		{"text/html", ""},
+		{"application/cbor", "application/cbor"},
+		{"Application/CBOR", "application/cbor"},
+		{"application/json, application/cbor", "application/json"},
+		{"application/cbor;q=0", ""},
+		{"application/cbor; q=0.0, application/octet-stream", "application/octet-stream"},
+		{"application/json;q=0, application/cbor", "application/cbor"},
+		{"application/cbor;q=0.9, application/octet-stream", "application/cbor"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.accept, func(t *testing.T) {
+			if result := negotiate(tc.accept, offers...); result != tc.expected {
+				t.Errorf("Expected %q, got %q", tc.expected, result)
+			}
+		})
+	}
+}
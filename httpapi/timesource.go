@@ -0,0 +1,27 @@
+package httpapi
+
+import (
+	"net/http"
+	"uidGenerator/timeprovider/fallback"
+)
+
+// TimeSource Is a time provider that reports which of its sources is in use
+type TimeSource interface {
+	Status() fallback.Status
+}
+
+// TimeSourceHandler Reports the source and drift of a fallback time provider
+func TimeSourceHandler(source TimeSource) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		status := source.Status()
+		body := map[string]interface{}{
+			"primary":   status.Primary,
+			"driftMs":   status.Drift.Milliseconds(),
+			"fallbacks": status.Fallbacks,
+		}
+		if status.Err != nil {
+			body["error"] = status.Err.Error()
+		}
+		writeJSON(w, http.StatusOK, body)
+	})
+}
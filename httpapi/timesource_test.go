@@ -0,0 +1,36 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+	"uidGenerator/timeprovider/fallback"
+)
+
+type stubTimeSource fallback.Status
+
+func (s stubTimeSource) Status() fallback.Status {
+	return fallback.Status(s)
+}
+
+func TestTimeSourceHandler(t *testing.T) {
+	source := stubTimeSource{Drift: 42 * time.Millisecond, Fallbacks: 3, Err: errors.New("time file is stale")}
+	rec := httptest.NewRecorder()
+	TimeSourceHandler(source).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/time", nil))
+
+	var response struct {
+		Primary   bool   `json:"primary"`
+		DriftMs   int64  `json:"driftMs"`
+		Fallbacks int64  `json:"fallbacks"`
+		Error     string `json:"error"`
+	}
+	if err := json.NewDecoder(rec.Body).Decode(&response); err != nil {
+		t.Fatalf("Expected JSON body, got %v", err)
+	}
+	if response.Primary || response.DriftMs != 42 || response.Fallbacks != 3 || response.Error != "time file is stale" {
+		t.Errorf("Expected the status of the source, got %+v", response)
+	}
+}
@@ -0,0 +1,64 @@
+package httpapi
+
+import (
+	"encoding/json"
+	"golang.org/x/net/websocket"
+	"net/http"
+	"uidGenerator/generator"
+)
+
+// WebSocketHandler Serves WebSocket sessions with the workers of pool
+func WebSocketHandler(pool *generator.Pool) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ServeWebSocket(w, r, pool)
+	})
+}
+
+// ServeWebSocket Keeps a socket open and answers every {"count": n} frame
+// with a batch of IDs. A worker of pool is only held while a batch is
+// generated, idle sessions hold none. The session remembers the last ID it
+// handed out and continues after it, so the IDs on one socket are strictly
+// increasing whichever worker generates them.
+func ServeWebSocket(w http.ResponseWriter, r *http.Request, pool *generator.Pool) {
+	websocket.Handler(func(ws *websocket.Conn) {
+		defer ws.Close()
+		var last int64
+		for {
+			var frame []byte
+			if err := websocket.Message.Receive(ws, &frame); err != nil {
+				return
+			}
+
+			var request struct {
+				Count int `json:"count"`
+			}
+			if err := json.Unmarshal(frame, &request); err != nil {
+				if websocket.JSON.Send(ws, map[string]interface{}{"error": "invalid request"}) != nil {
+					return
+				}
+				continue
+			}
+
+			if request.Count > generator.MaxBatch {
+				if websocket.JSON.Send(ws, map[string]interface{}{"error": generator.ErrBatchTooLarge.Error()}) != nil {
+					return
+				}
+				continue
+			}
+
+			ids := make([]int64, max(request.Count, 1))
+			_, err := pool.GenerateAfter(ids, last)
+			if err != nil {
+				if websocket.JSON.Send(ws, map[string]interface{}{"error": err.Error()}) != nil {
+					return
+				}
+				continue
+			}
+
+			last = ids[len(ids)-1]
+			if websocket.JSON.Send(ws, map[string]interface{}{"ids": ids}) != nil {
+				return
+			}
+		}
+	}).ServeHTTP(w, r)
+}
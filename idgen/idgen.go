@@ -0,0 +1,202 @@
+// Package idgen embeds the ID generator in-process. It wraps the workers of
+// the generator package behind a small, stable API:
+//
+//	g, err := idgen.New(idgen.WithWorkerID(3))
+//	id, err := g.Next()
+package idgen
+
+import (
+	"context"
+	"fmt"
+	"iter"
+	"time"
+	"uidGenerator/generator"
+	"uidGenerator/timeprovider"
+	"uidGenerator/timeprovider/epoch"
+)
+
+// DefaultOffset is the epoch offset used when no time provider is given, it
+// corresponds to January 1, 2015
+const DefaultOffset int64 = 1420070400000
+
+// Generator Hands out IDs from a pool of workers sharing one worker ID
+type Generator struct {
+	pool   *generator.Pool
+	layout generator.Layout
+}
+
+type config struct {
+	layout       generator.Layout
+	workerId     int64
+	provider     timeprovider.FallibleTimeProvider
+	clockPolicy  generator.ClockPolicy
+	maxClockWait time.Duration
+	poolSize     int64
+	shards       int
+	borrow       bool
+}
+
+// Option Configures a Generator
+type Option func(*config)
+
+// WithLayout Sets the bit layout of the IDs
+func WithLayout(layout generator.Layout) Option {
+	return func(c *config) {
+		c.layout = layout
+	}
+}
+
+// WithWorkerID Sets the node ID embedded in every ID
+func WithWorkerID(workerId int64) Option {
+	return func(c *config) {
+		c.workerId = workerId
+	}
+}
+
+// WithTimeProvider Sets the clock the timestamps are read from. Providers
+// with a checked variant, such as julian, report their errors through it.
+func WithTimeProvider(provider timeprovider.TimeProvider) Option {
+	return func(c *config) {
+		c.provider = nil
+		if provider != nil {
+			c.provider = timeprovider.Adapt(provider)
+		}
+	}
+}
+
+// WithFallibleTimeProvider Sets a clock that can fail, its errors are
+// returned by Next and NextN wrapped in generator.ErrTimeProvider
+func WithFallibleTimeProvider(provider timeprovider.FallibleTimeProvider) Option {
+	return func(c *config) {
+		c.provider = provider
+	}
+}
+
+// WithClockPolicy Sets the reaction to the clock going backwards, maxWait
+// bounds the wait of generator.ClockWait
+func WithClockPolicy(policy generator.ClockPolicy, maxWait time.Duration) Option {
+	return func(c *config) {
+		c.clockPolicy = policy
+		c.maxClockWait = maxWait
+	}
+}
+
+// WithPoolSize Sets how many workers, each with its own thread ID, serve
+// concurrent callers. It defaults to every thread ID of the layout.
+func WithPoolSize(size int) Option {
+	return func(c *config) {
+		c.poolSize = int64(size)
+	}
+}
+
+// WithShards Splits the pool into shards with disjoint thread IDs, so
+// parallel callers contend less. runtime.GOMAXPROCS(0) is a good choice.
+func WithShards(shards int) Option {
+	return func(c *config) {
+		c.shards = shards
+	}
+}
+
+// WithBorrowing Lets large batches take counter space from idle workers
+// instead of waiting for the next timestamp, see generator.Pool.EnableBorrowing
+func WithBorrowing() Option {
+	return func(c *config) {
+		c.borrow = true
+	}
+}
+
+// New Creates a Generator. Without options it behaves like the server
+// defaults: worker ID 1, epoch time since 2015 and the default layout.
+func New(opts ...Option) (*Generator, error) {
+	c := config{
+		layout:   generator.DefaultLayout(),
+		workerId: 1,
+	}
+	for _, opt := range opts {
+		opt(&c)
+	}
+
+	if err := c.layout.Validate(); err != nil {
+		return nil, err
+	}
+	if c.workerId < 0 || c.workerId > c.layout.MaxWorkerID() {
+		return nil, fmt.Errorf("worker ID %d is out of range (0-%d)", c.workerId, c.layout.MaxWorkerID())
+	}
+	if c.poolSize == 0 {
+		c.poolSize = c.layout.MaxThreadID()
+	}
+	if c.poolSize < 0 || c.poolSize > c.layout.MaxThreadID() {
+		return nil, fmt.Errorf("pool size %d is out of range (1-%d)", c.poolSize, c.layout.MaxThreadID())
+	}
+	if c.provider == nil {
+		c.provider = timeprovider.Adapt(epoch.New(DefaultOffset))
+	}
+
+	layout := c.layout
+	workers := make([]*generator.WorkerVariant, c.poolSize)
+	for i := range workers {
+		workers[i] = &generator.WorkerVariant{
+			WorkerID:             c.workerId,
+			ThreadId:             int64(i) + 1,
+			FallibleTimeProvider: c.provider,
+			Layout:               &layout,
+			ClockPolicy:          c.clockPolicy,
+			MaxClockWait:         c.maxClockWait,
+		}
+	}
+
+	pool := generator.NewShardedPoolOf(workers, c.shards)
+	if c.borrow {
+		pool.EnableBorrowing()
+	}
+	return &Generator{
+		pool:   pool,
+		layout: layout,
+	}, nil
+}
+
+// Next Returns a single ID
+func (g *Generator) Next() (int64, error) {
+	ids, err := g.pool.GenerateID(1)
+	if err != nil {
+		return 0, err
+	}
+	return ids[0], nil
+}
+
+// NextN Returns n IDs, at most generator.MaxBatch. They increase within the batch unless WithBorrowing
+// spreads it over several workers or part of it comes from the cache.
+func (g *Generator) NextN(n int) ([]int64, error) {
+	if n <= 0 {
+		return nil, fmt.Errorf("invalid number of IDs %d", n)
+	}
+	return g.pool.GenerateID(n)
+}
+
+// All Returns an endless sequence of IDs for range-over-func loops, it stops
+// early when generation fails
+func (g *Generator) All() iter.Seq[int64] {
+	return g.pool.All()
+}
+
+// Stream Generates IDs in the background, up to bufferSize ahead of the
+// consumer, until ctx is done
+func (g *Generator) Stream(ctx context.Context, bufferSize int) <-chan int64 {
+	return g.pool.Stream(ctx, bufferSize)
+}
+
+// Decode Splits an ID of this generator into its fields
+func (g *Generator) Decode(id int64) generator.Components {
+	return g.layout.Decode(id)
+}
+
+// Layout Returns the bit layout of the IDs
+func (g *Generator) Layout() generator.Layout {
+	return g.layout
+}
+
+// Pool Returns the workers backing the generator, for serving them over the
+// network
+func (g *Generator) Pool() *generator.Pool {
+	return g.pool
+}
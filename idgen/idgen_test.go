@@ -0,0 +1,175 @@
+package idgen
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"uidGenerator/generator"
+	"uidGenerator/timeprovider/fake"
+	"uidGenerator/timeprovider/julian"
+)
+
+func TestNew_Defaults(t *testing.T) {
+	g, err := New()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	id, err := g.Next()
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	components := g.Decode(id)
+	if components.WorkerID != 1 {
+		t.Errorf("Expected worker ID 1, got %d", components.WorkerID)
+	}
+	if g.Layout() != generator.DefaultLayout() {
+		t.Errorf("Expected default layout, got %+v", g.Layout())
+	}
+}
+
+func TestNew_Options(t *testing.T) {
+	layout := generator.Layout{TimestampBits: 39, WorkerBits: 16, ThreadBits: 2, CounterBits: 6}
+	provider := julian.New(2000100000)
+	g, err := New(
+		WithLayout(layout),
+		WithWorkerID(1000),
+		WithTimeProvider(provider),
+		WithClockPolicy(generator.ClockWait, 0),
+		WithPoolSize(2),
+	)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	ids, err := g.NextN(10)
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	for _, id := range ids {
+		components := g.Decode(id)
+		if components.WorkerID != 1000 {
+			t.Errorf("Expected worker ID 1000, got %d", components.WorkerID)
+		}
+		if components.ThreadId < 1 || components.ThreadId > 2 {
+			t.Errorf("Expected thread ID 1 or 2, got %d", components.ThreadId)
+		}
+		if components.Timestamp <= 0 || components.Timestamp > provider.GetTimeStamp() {
+			t.Errorf("Expected Julian timestamp, got %d", components.Timestamp)
+		}
+	}
+
+	// Both workers are handed out, then the pool is empty
+	first := g.Pool().Acquire()
+	second := g.Pool().Acquire()
+	if first.ThreadId == second.ThreadId {
+		t.Error("Expected distinct thread IDs")
+	}
+	g.Pool().Release(first)
+	g.Pool().Release(second)
+}
+
+func TestNew_InvalidOptions(t *testing.T) {
+	testCases := []struct {
+		name string
+		opts []Option
+	}{
+		{"Worker ID too large", []Option{WithWorkerID(8)}},
+		{"Negative worker ID", []Option{WithWorkerID(-1)}},
+		{"Pool too large", []Option{WithPoolSize(32)}},
+		{"Negative pool size", []Option{WithPoolSize(-1)}},
+		{"Invalid layout", []Option{WithLayout(generator.Layout{TimestampBits: 60, WorkerBits: 3, ThreadBits: 5, CounterBits: 10})}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			if _, err := New(tc.opts...); err == nil {
+				t.Error("Expected error")
+			}
+		})
+	}
+}
+
+func TestNextN_Invalid(t *testing.T) {
+	g, _ := New()
+	if _, err := g.NextN(0); err == nil {
+		t.Error("Expected error for zero IDs")
+	}
+}
+
+func TestNext_Concurrent(t *testing.T) {
+	g, err := New(WithPoolSize(4))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	var mutex sync.Mutex
+	var wg sync.WaitGroup
+	idMap := make(map[int64]bool)
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 20; j++ {
+				id, err := g.Next()
+				if err != nil {
+					t.Errorf("Expected no error, got %v", err)
+					return
+				}
+				mutex.Lock()
+				if idMap[id] {
+					t.Errorf("Duplicate ID found: %d", id)
+				}
+				idMap[id] = true
+				mutex.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+}
+
+func TestAll(t *testing.T) {
+	g, _ := New()
+
+	var last int64
+	count := 0
+	for id := range g.All() {
+		if id == last {
+			t.Errorf("Duplicate ID found: %d", id)
+		}
+		last = id
+		count++
+		if count == 100 {
+			break
+		}
+	}
+}
+
+func TestNew_Shards(t *testing.T) {
+	g, err := New(WithShards(4))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	ids, err := g.NextN(5)
+	if err != nil || len(ids) != 5 {
+		t.Errorf("Expected 5 IDs, got %v and %v", ids, err)
+	}
+}
+
+func TestWithFallibleTimeProvider(t *testing.T) {
+	clock := fake.NewFallible(fake.NewClock(100))
+	g, err := New(WithFallibleTimeProvider(clock))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	if _, err := g.Next(); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+
+	broken := errors.New("time source offline")
+	clock.SetError(broken)
+	if _, err := g.NextN(3); !errors.Is(err, generator.ErrTimeProvider) || !errors.Is(err, broken) {
+		t.Errorf("Expected ErrTimeProvider wrapping the provider error, got %v", err)
+	}
+}
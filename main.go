@@ -1,22 +1,63 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
+	"log"
+	"os"
+	"runtime"
 	"strconv"
+	"time"
+	_ "time/tzdata"
+	"uidGenerator/address"
+	"uidGenerator/generator"
 	"uidGenerator/handler"
-	generatorMiddleware "uidGenerator/middleware"
+	"uidGenerator/httpapi"
+	"uidGenerator/idgen"
+	"uidGenerator/memcache"
+	"uidGenerator/resp"
+	"uidGenerator/tcp"
 	"uidGenerator/timeprovider"
 	"uidGenerator/timeprovider/epoch"
+	"uidGenerator/timeprovider/fallback"
+	"uidGenerator/timeprovider/hlc"
 	"uidGenerator/timeprovider/julian"
+	"uidGenerator/timeprovider/logical"
+	"uidGenerator/timeprovider/monotonic"
+	"uidGenerator/timeprovider/tai"
 )
 
 var (
 	portNumber   = flag.Int("port", 1323, "Port number")
 	workerId     = flag.Int64("workerId", 1, "Worker ID")
-	timeProvider = flag.String("timeProvider", "epoch", "Time provider (julian or epoch)")
+	timeProvider = flag.String("timeProvider", "epoch", "Time provider (julian, epoch, monotonic, hlc, logical, tai, gps or file)")
 	offset       = flag.Int64("offset", 1420070400000, "Offset for the time provider")
+	precision    = flag.Int("julianPrecision", 0, "Sub-second digits of the julian time provider (0-3)")
+	fullYear     = flag.Bool("julianFullYear", false, "Encode all four year digits with the julian time provider")
+	timezone     = flag.String("timezone", "", "IANA time zone whose wall clock the julian time provider encodes, UTC when empty")
+	tick         = flag.Duration("tick", time.Millisecond, "Length of a timestamp unit of the epoch time provider, e.g. 10ms")
+	resync       = flag.Duration("resyncInterval", 0, "How often the monotonic time provider slews towards the wall clock (disabled when 0)")
+	maxSlew      = flag.Float64("maxSlew", monotonic.DefaultMaxSlew, "Largest correction of the monotonic time provider per elapsed time")
+	logicalBits  = flag.Int64("hlcLogicalBits", hlc.DefaultLogicalBits, "Bits of the logical component of the hlc time provider")
+	maxDrift     = flag.Duration("hlcMaxDrift", hlc.DefaultMaxDrift, "Largest lead of peer timestamps the hlc time provider accepts (unbounded when 0)")
+	stateFile    = flag.String("stateFile", "logical.state", "File persisting the counter of the logical time provider")
+	leapSeconds  = flag.String("leapSeconds", "", "Leap second table in the leap-seconds.list format, the embedded one when empty")
+	leapSmear    = flag.Bool("leapSmear", false, "Let the tai time provider return UTC with leap seconds smeared over 24 hours")
+	timeFile     = flag.String("timeFile", "", "File a time daemon rewrites with the Unix time in milliseconds, read by the file time provider")
+	timeFileAge  = flag.Duration("timeFileMaxAge", 2*time.Second, "Age after which the time file is stale and the system clock is used")
+	timeDrift    = flag.Duration("maxTimeDrift", fallback.DefaultMaxDrift, "Drift between the time file and the system clock that is logged")
+	tcpPort      = flag.Int("tcpPort", 0, "Port number for the binary TCP protocol (disabled when 0)")
+	listen       = flag.String("listen", "", "HTTP listen address (host:port or unix:///path), overrides port")
+	tcpListen    = flag.String("tcpListen", "", "Binary protocol listen address (host:port or unix:///path), overrides tcpPort")
+	respListen   = flag.String("respListen", "", "Redis protocol listen address (host:port or unix:///path, disabled when empty)")
+	mcListen     = flag.String("memcacheListen", "", "Memcached protocol listen address (host:port or unix:///path, disabled when empty)")
+	cacheSize    = flag.Int("cacheSize", 0, "Number of IDs to pre-generate while idle (disabled when 0)")
+	cacheMaxAge  = flag.Duration("cacheMaxAge", time.Second, "Time since caching after which cached IDs are dropped (never when 0)")
+	shards       = flag.Int("shards", 1, "Number of worker pool shards, 0 for one per CPU")
+	borrow       = flag.Bool("borrow", false, "Let large batches borrow counter space from idle threads instead of waiting")
+	socketMode   = flag.Uint("socketMode", 0660, "File permissions of Unix domain sockets")
 )
 
 func main() {
@@ -26,23 +67,160 @@ func main() {
 	var provider timeprovider.TimeProvider
 	switch *timeProvider {
 	case "epoch":
-		provider = epoch.New(*offset)
+		provider = epoch.New(*offset, epoch.WithTick(*tick))
 	case "julian":
-		provider = julian.New(*offset)
+		julianOptions := []julian.Option{julian.WithPrecision(*precision)}
+		if *fullYear {
+			julianOptions = append(julianOptions, julian.WithFullYear())
+		}
+		if *timezone != "" {
+			location, err := time.LoadLocation(*timezone)
+			if err != nil {
+				panic(err)
+			}
+			julianOptions = append(julianOptions, julian.WithLocation(location))
+		}
+		julianProvider := julian.New(*offset, append(julianOptions, julian.WithTimeStampBits(generator.EpochBits))...)
+		if err := julianProvider.Validate(); err != nil {
+			panic(err)
+		}
+		provider = julianProvider
+	case "monotonic":
+		provider = monotonic.New(*offset, monotonic.WithResync(*resync, *maxSlew))
+	case "hlc":
+		provider = hlc.New(*offset, hlc.WithLogicalBits(*logicalBits), hlc.WithMaxDrift(*maxDrift), hlc.WithTimeStampBits(generator.EpochBits))
+	case "logical":
+		var err error
+		provider, err = logical.New(*stateFile, logical.WithTimeStampBits(generator.EpochBits))
+		if err != nil {
+			panic(err)
+		}
+	case "tai", "gps":
+		table := tai.DefaultTable()
+		if *leapSeconds != "" {
+			var err error
+			if table, err = tai.LoadTable(*leapSeconds); err != nil {
+				panic(err)
+			}
+		}
+		if table.Expired(time.Now()) {
+			log.Printf("Leap second table expired on %s, pass a newer list with --leapSeconds", table.Expires().Format(time.DateOnly))
+		}
+		taiOptions := []tai.Option{tai.WithTable(table)}
+		if *timeProvider == "gps" {
+			taiOptions = append(taiOptions, tai.WithScale(tai.GPS))
+		}
+		if *leapSmear {
+			taiOptions = append(taiOptions, tai.WithLeapSmear())
+		}
+		provider = tai.New(*offset, taiOptions...)
+	case "file":
+		if *timeFile == "" {
+			panic("The file time provider needs --timeFile")
+		}
+		provider = fallback.New(*offset, fallback.NewFileSource(*timeFile, *timeFileAge), fallback.WithMaxDrift(*timeDrift))
 	default:
 		panic("Unknown time provider")
 	}
 
+	// Generator whose workers are shared by all listeners
+	if *shards == 0 {
+		*shards = runtime.GOMAXPROCS(0)
+	}
+	options := []idgen.Option{
+		idgen.WithWorkerID(*workerId),
+		idgen.WithTimeProvider(provider),
+		idgen.WithShards(*shards),
+	}
+	if *borrow {
+		options = append(options, idgen.WithBorrowing())
+	}
+	idGenerator, err := idgen.New(options...)
+	if err != nil {
+		panic(err)
+	}
+	pool := idGenerator.Pool()
+	if provider, ok := provider.(interface {
+		Capacity(timestampBits, counterBits int64) epoch.Capacity
+	}); ok {
+		layout := idGenerator.Layout()
+		capacity := provider.Capacity(layout.TimestampBits, layout.CounterBits)
+		log.Printf("Timestamps of %v last %.0f years, until %s; each thread issues up to %d IDs per tick (%.0f per second)",
+			capacity.Tick, capacity.Years, capacity.Exhausted.Format(time.DateOnly), capacity.IDsPerTick, capacity.IDsPerSecond)
+	}
+	var cache *generator.Cache
+	if *cacheSize > 0 {
+		cache = pool.EnableCache(context.Background(), generator.CacheConfig{
+			LowWatermark:  *cacheSize / 2,
+			HighWatermark: *cacheSize,
+			MaxAge:        *cacheMaxAge,
+		})
+	}
+
 	// Echo instance
 	e := echo.New()
 
 	// Middleware
-	e.Use(generatorMiddleware.GeneratorProvider(*workerId, provider))
 	e.Use(middleware.Logger())
 
 	// Routes
-	e.GET("/", handler.Generator)
+	rootHandler := httpapi.Handler(pool)
+	if clock, ok := provider.(httpapi.Clock); ok {
+		rootHandler = httpapi.ObserveClock(clock, rootHandler)
+	}
+	e.GET("/", echo.WrapHandler(rootHandler))
+	e.GET("/ws", handler.WebSocket(pool))
+	if cache != nil {
+		e.GET("/cache", echo.WrapHandler(httpapi.CacheStatsHandler(cache)))
+	}
+	if source, ok := provider.(httpapi.TimeSource); ok {
+		e.GET("/time", echo.WrapHandler(httpapi.TimeSourceHandler(source)))
+	}
+
+	// Binary protocol
+	if *tcpListen == "" && *tcpPort != 0 {
+		*tcpListen = ":" + strconv.Itoa(*tcpPort)
+	}
+	if *tcpListen != "" {
+		listener, err := address.Listen(*tcpListen, os.FileMode(*socketMode))
+		if err != nil {
+			e.Logger.Fatal(err)
+		}
+		go func() {
+			e.Logger.Fatal(tcp.Serve(listener, pool))
+		}()
+	}
+
+	// Redis protocol
+	if *respListen != "" {
+		listener, err := address.Listen(*respListen, os.FileMode(*socketMode))
+		if err != nil {
+			e.Logger.Fatal(err)
+		}
+		go func() {
+			e.Logger.Fatal(resp.Serve(listener, pool))
+		}()
+	}
+
+	// Memcached protocol
+	if *mcListen != "" {
+		listener, err := address.Listen(*mcListen, os.FileMode(*socketMode))
+		if err != nil {
+			e.Logger.Fatal(err)
+		}
+		go func() {
+			e.Logger.Fatal(memcache.Serve(listener, pool))
+		}()
+	}
 
 	// Start server
-	e.Logger.Fatal(e.Start(":" + strconv.Itoa(*portNumber)))
+	if *listen == "" {
+		*listen = ":" + strconv.Itoa(*portNumber)
+	}
+	listener, err := address.Listen(*listen, os.FileMode(*socketMode))
+	if err != nil {
+		e.Logger.Fatal(err)
+	}
+	e.Listener = listener
+	e.Logger.Fatal(e.Start(""))
 }
@@ -0,0 +1,149 @@
+package memcache
+
+import (
+	"bufio"
+	"errors"
+	"net"
+	"strconv"
+	"strings"
+	"uidGenerator/generator"
+)
+
+// MaxBatch is the largest number of IDs a batch:<n> key may ask for
+const MaxBatch = 1 << 16
+
+// Version is reported by the version command
+const Version = "idGenerator"
+
+// Serve Accepts memcached text protocol connections on l until l is closed.
+// Only retrievals are supported:
+//
+//	get nextid       one ID
+//	get batch:<n>    n comma separated IDs
+//
+// Several keys may be requested at once, unknown keys are reported as misses.
+// gets behaves like get with a cas unique of 0.
+func Serve(l net.Listener, pool *generator.Pool) error {
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return nil
+			}
+			return err
+		}
+		go serveConn(conn, pool)
+	}
+}
+
+func serveConn(conn net.Conn, pool *generator.Pool) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	w := bufio.NewWriter(conn)
+
+	for {
+		line, err := r.ReadSlice('\n')
+		if err == bufio.ErrBufferFull {
+			w.WriteString("CLIENT_ERROR line too long\r\n")
+			w.Flush()
+			return
+		}
+		if err != nil {
+			return
+		}
+
+		fields := strings.Fields(string(line))
+		if len(fields) > 0 && fields[0] == "quit" {
+			w.Flush()
+			return
+		}
+		execute(w, fields, pool)
+
+		// Pipelined commands are answered in one write once the input runs dry
+		if r.Buffered() == 0 {
+			if err := w.Flush(); err != nil {
+				return
+			}
+		}
+	}
+}
+
+func execute(w *bufio.Writer, fields []string, pool *generator.Pool) {
+	if len(fields) == 0 {
+		w.WriteString("ERROR\r\n")
+		return
+	}
+
+	switch fields[0] {
+	case "get", "gets":
+		if len(fields) < 2 {
+			w.WriteString("ERROR\r\n")
+			return
+		}
+		// Values are generated before anything is written, so an error never
+		// leaves a partial response behind
+		values := make([]string, len(fields)-1)
+		for i, key := range fields[1:] {
+			value, err := lookup(key, pool)
+			if err != nil {
+				w.WriteString(err.Error())
+				w.WriteString("\r\n")
+				return
+			}
+			values[i] = value
+		}
+		for i, key := range fields[1:] {
+			if values[i] == "" {
+				continue
+			}
+			w.WriteString("VALUE ")
+			w.WriteString(key)
+			w.WriteString(" 0 ")
+			w.WriteString(strconv.Itoa(len(values[i])))
+			if fields[0] == "gets" {
+				w.WriteString(" 0")
+			}
+			w.WriteString("\r\n")
+			w.WriteString(values[i])
+			w.WriteString("\r\n")
+		}
+		w.WriteString("END\r\n")
+
+	case "version":
+		w.WriteString("VERSION " + Version + "\r\n")
+
+	default:
+		w.WriteString("ERROR\r\n")
+	}
+}
+
+// lookup Returns the value of key, or an empty string for a miss. Errors are
+// already formatted as protocol error lines.
+func lookup(key string, pool *generator.Pool) (string, error) {
+	n := 1
+	if key != "nextid" {
+		count, found := strings.CutPrefix(key, "batch:")
+		if !found {
+			return "", nil
+		}
+		var err error
+		n, err = strconv.Atoi(count)
+		if err != nil || n <= 0 || n > MaxBatch {
+			return "", errors.New("CLIENT_ERROR batch size must be between 1 and " + strconv.Itoa(MaxBatch))
+		}
+	}
+
+	ids, err := pool.GenerateID(n)
+	if err != nil {
+		return "", errors.New("SERVER_ERROR " + err.Error())
+	}
+
+	var b strings.Builder
+	for i, id := range ids {
+		if i > 0 {
+			b.WriteByte(',')
+		}
+		b.WriteString(strconv.FormatInt(id, 10))
+	}
+	return b.String(), nil
+}
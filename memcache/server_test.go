@@ -0,0 +1,166 @@
+package memcache
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+	"uidGenerator/generator"
+	"uidGenerator/timeprovider/epoch"
+)
+
+func dial(t *testing.T) net.Conn {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	go Serve(listener, generator.NewPool(1, epoch.New(1420070400000)))
+	t.Cleanup(func() { listener.Close() })
+
+	conn, err := net.Dial("tcp", listener.Addr().String())
+	if err != nil {
+		t.Fatalf("Failed to dial: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+// Transcripts as a memcached client would send and expect them. In the
+// expected responses <id> stands for any ID and <len> for the value length.
+var transcripts = []struct {
+	name     string
+	request  string
+	response string
+}{
+	{
+		"get nextid",
+		"get nextid\r\n",
+		"VALUE nextid 0 <len>\r\n<id>\r\nEND\r\n",
+	},
+	{
+		"get batch",
+		"get batch:3\r\n",
+		"VALUE batch:3 0 <len>\r\n<id>,<id>,<id>\r\nEND\r\n",
+	},
+	{
+		"gets",
+		"gets nextid\r\n",
+		"VALUE nextid 0 <len> 0\r\n<id>\r\nEND\r\n",
+	},
+	{
+		"multiple keys with miss",
+		"get nextid missing batch:2\r\n",
+		"VALUE nextid 0 <len>\r\n<id>\r\nVALUE batch:2 0 <len>\r\n<id>,<id>\r\nEND\r\n",
+	},
+	{
+		"miss",
+		"get session:42\r\n",
+		"END\r\n",
+	},
+	{
+		"pipelined",
+		"get nextid\r\nget nextid\r\n",
+		"VALUE nextid 0 <len>\r\n<id>\r\nEND\r\nVALUE nextid 0 <len>\r\n<id>\r\nEND\r\n",
+	},
+	{
+		"invalid batch",
+		"get batch:0\r\n",
+		"CLIENT_ERROR batch size must be between 1 and 65536\r\n",
+	},
+	{
+		"get without key",
+		"get\r\n",
+		"ERROR\r\n",
+	},
+	{
+		"unsupported command",
+		"delete nextid\r\n",
+		"ERROR\r\n",
+	},
+	{
+		"version",
+		"version\r\n",
+		"VERSION idGenerator\r\n",
+	},
+}
+
+func expectation(response string) *regexp.Regexp {
+	pattern := regexp.QuoteMeta(response)
+	pattern = strings.ReplaceAll(pattern, "<id>", `(\d+)`)
+	pattern = strings.ReplaceAll(pattern, "<len>", `(\d+)`)
+	return regexp.MustCompile("^" + pattern + "$")
+}
+
+func TestServe_Transcripts(t *testing.T) {
+	for _, tc := range transcripts {
+		t.Run(tc.name, func(t *testing.T) {
+			conn := dial(t)
+			if _, err := conn.Write([]byte(tc.request)); err != nil {
+				t.Fatalf("Failed to write request: %v", err)
+			}
+
+			expected := expectation(tc.response)
+			response := readResponse(t, conn, len(strings.Split(tc.response, "\r\n"))-1)
+			if !expected.MatchString(response) {
+				t.Errorf("Expected response matching %q, got %q", tc.response, response)
+			}
+		})
+	}
+}
+
+func TestServe_ValueLength(t *testing.T) {
+	conn := dial(t)
+	if _, err := conn.Write([]byte("get batch:5\r\n")); err != nil {
+		t.Fatalf("Failed to write request: %v", err)
+	}
+
+	r := bufio.NewReader(conn)
+	header, _ := r.ReadString('\n')
+	fields := strings.Fields(header)
+	length, err := strconv.Atoi(fields[3])
+	if err != nil {
+		t.Fatalf("Invalid header %q", header)
+	}
+
+	// Clients read exactly the announced number of bytes
+	value := make([]byte, length+2)
+	if _, err := io.ReadFull(r, value); err != nil {
+		t.Fatalf("Failed to read value: %v", err)
+	}
+	if !strings.HasSuffix(string(value), "\r\n") {
+		t.Errorf("Expected value to end with CRLF, got %q", value)
+	}
+	if ids := strings.Split(strings.TrimSuffix(string(value), "\r\n"), ","); len(ids) != 5 {
+		t.Errorf("Expected 5 IDs, got %d", len(ids))
+	}
+}
+
+func TestServe_Quit(t *testing.T) {
+	conn := dial(t)
+	if _, err := conn.Write([]byte("quit\r\n")); err != nil {
+		t.Fatalf("Failed to write request: %v", err)
+	}
+	conn.SetReadDeadline(time.Now().Add(time.Second))
+	if _, err := conn.Read(make([]byte, 1)); err != io.EOF {
+		t.Errorf("Expected connection to be closed, got %v", err)
+	}
+}
+
+// readResponse Reads the given number of CRLF terminated lines
+func readResponse(t *testing.T, conn net.Conn, lines int) string {
+	conn.SetReadDeadline(time.Now().Add(time.Second))
+	r := bufio.NewReader(conn)
+	var b strings.Builder
+	for i := 0; i < lines; i++ {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			t.Fatalf("Failed to read response: %v", err)
+		}
+		b.WriteString(line)
+	}
+	return b.String()
+}
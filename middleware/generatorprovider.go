@@ -7,23 +7,19 @@ import (
 )
 
 func GeneratorProvider(workerId int64, provider timeprovider.TimeProvider) echo.MiddlewareFunc {
-	workers := make(chan *generator.WorkerVariant, generator.ThreadCap)
-	var i int64
-	for i = 1; i <= generator.ThreadCap; i++ {
-		worker := &generator.WorkerVariant{
-			WorkerID:     workerId,
-			ThreadId:     i,
-			TimeProvider: provider,
-		}
-		workers <- worker
-	}
+	return PoolProvider(generator.NewPool(workerId, provider))
+}
+
+// PoolProvider Lends a worker of the pool to every request, so the pool can be
+// shared with the other listeners of the node
+func PoolProvider(pool *generator.Pool) echo.MiddlewareFunc {
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(c echo.Context) error {
-			worker := <-workers
+			worker := pool.Acquire()
 			c.Set("worker", worker)
 			c.Logger().Debugf("worker %d", worker.WorkerID)
 			defer func() {
-				workers <- worker
+				pool.Release(worker)
 			}()
 			return next(c)
 		}
@@ -0,0 +1,228 @@
+package resp
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"uidGenerator/generator"
+)
+
+// MaxBatch is the largest number of IDs IDGEN.BATCH may ask for
+const MaxBatch = 1 << 16
+
+// maxBulkLength bounds the arguments a client may send
+const maxBulkLength = 1 << 10
+
+var errProtocol = errors.New("protocol error")
+
+// Serve Accepts RESP2 connections on l until l is closed. Supported commands:
+//
+//	IDGEN.NEXT        integer reply with one ID
+//	IDGEN.BATCH n     array reply with n IDs
+//	IDGEN.DECODE id   array reply of field names and values
+//	PING [message]
+//	QUIT
+func Serve(l net.Listener, pool *generator.Pool) error {
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return nil
+			}
+			return err
+		}
+		go serveConn(conn, pool)
+	}
+}
+
+func serveConn(conn net.Conn, pool *generator.Pool) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	w := bufio.NewWriter(conn)
+
+	for {
+		args, err := readCommand(r)
+		if err != nil {
+			if errors.Is(err, errProtocol) {
+				writeError(w, "Protocol error")
+				w.Flush()
+			}
+			return
+		}
+
+		quit := len(args) > 0 && strings.EqualFold(args[0], "QUIT")
+		if len(args) > 0 {
+			execute(w, args, pool)
+		}
+
+		// Pipelined commands are answered in one write once the input runs dry
+		if r.Buffered() == 0 || quit {
+			if err := w.Flush(); err != nil || quit {
+				return
+			}
+		}
+	}
+}
+
+func execute(w *bufio.Writer, args []string, pool *generator.Pool) {
+	switch strings.ToUpper(args[0]) {
+	case "IDGEN.NEXT":
+		if len(args) != 1 {
+			writeArityError(w, args[0])
+			return
+		}
+		ids, err := pool.GenerateID(1)
+		if err != nil {
+			writeError(w, err.Error())
+			return
+		}
+		writeInteger(w, ids[0])
+
+	case "IDGEN.BATCH":
+		if len(args) != 2 {
+			writeArityError(w, args[0])
+			return
+		}
+		n, err := strconv.Atoi(args[1])
+		if err != nil || n <= 0 || n > MaxBatch {
+			writeError(w, "batch size must be between 1 and "+strconv.Itoa(MaxBatch))
+			return
+		}
+		ids, err := pool.GenerateID(n)
+		if err != nil {
+			writeError(w, err.Error())
+			return
+		}
+		writeArrayHeader(w, len(ids))
+		for _, id := range ids {
+			writeInteger(w, id)
+		}
+
+	case "IDGEN.DECODE":
+		if len(args) != 2 {
+			writeArityError(w, args[0])
+			return
+		}
+		id, err := strconv.ParseInt(args[1], 10, 64)
+		if err != nil {
+			writeError(w, "value is not an integer or out of range")
+			return
+		}
+		components := generator.Decode(id)
+		writeArrayHeader(w, 8)
+		writeBulkString(w, "timestamp")
+		writeInteger(w, components.Timestamp)
+		writeBulkString(w, "workerId")
+		writeInteger(w, components.WorkerID)
+		writeBulkString(w, "threadId")
+		writeInteger(w, components.ThreadId)
+		writeBulkString(w, "counter")
+		writeInteger(w, components.Counter)
+
+	case "PING":
+		switch len(args) {
+		case 1:
+			w.WriteString("+PONG\r\n")
+		case 2:
+			writeBulkString(w, args[1])
+		default:
+			writeArityError(w, args[0])
+		}
+
+	case "QUIT":
+		w.WriteString("+OK\r\n")
+
+	default:
+		writeError(w, "unknown command '"+args[0]+"'")
+	}
+}
+
+// readCommand Reads a command sent either as an array of bulk strings or as
+// an inline command line
+func readCommand(r *bufio.Reader) ([]string, error) {
+	line, err := readLine(r)
+	if err != nil {
+		return nil, err
+	}
+	if !strings.HasPrefix(line, "*") {
+		return strings.Fields(line), nil
+	}
+
+	n, err := strconv.Atoi(line[1:])
+	if err != nil || n > maxBulkLength {
+		return nil, errProtocol
+	}
+	args := make([]string, 0, max(n, 0))
+	for i := 0; i < n; i++ {
+		line, err := readLine(r)
+		if err != nil {
+			return nil, err
+		}
+		if !strings.HasPrefix(line, "$") {
+			return nil, errProtocol
+		}
+		length, err := strconv.Atoi(line[1:])
+		if err != nil || length < 0 || length > maxBulkLength {
+			return nil, errProtocol
+		}
+		bulk := make([]byte, length+2)
+		if _, err := io.ReadFull(r, bulk); err != nil {
+			return nil, err
+		}
+		if string(bulk[length:]) != "\r\n" {
+			return nil, errProtocol
+		}
+		args = append(args, string(bulk[:length]))
+	}
+	return args, nil
+}
+
+// readLine Reads one line, lines longer than the read buffer are rejected
+func readLine(r *bufio.Reader) (string, error) {
+	line, err := r.ReadSlice('\n')
+	if err == bufio.ErrBufferFull {
+		return "", errProtocol
+	}
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(string(line), "\r\n"), nil
+}
+
+func writeInteger(w *bufio.Writer, v int64) {
+	w.WriteByte(':')
+	w.WriteString(strconv.FormatInt(v, 10))
+	w.WriteString("\r\n")
+}
+
+func writeArrayHeader(w *bufio.Writer, n int) {
+	w.WriteByte('*')
+	w.WriteString(strconv.Itoa(n))
+	w.WriteString("\r\n")
+}
+
+func writeBulkString(w *bufio.Writer, s string) {
+	w.WriteByte('$')
+	w.WriteString(strconv.Itoa(len(s)))
+	w.WriteString("\r\n")
+	w.WriteString(s)
+	w.WriteString("\r\n")
+}
+
+// writeError Writes an error reply. Line breaks in the message, which may
+// echo client input, are replaced by spaces as Redis does, so they cannot end
+// the reply early.
+func writeError(w *bufio.Writer, message string) {
+	w.WriteString("-ERR ")
+	w.WriteString(lineBreaks.Replace(message))
+	w.WriteString("\r\n")
+}
+
+var lineBreaks = strings.NewReplacer("\r", " ", "\n", " ")
+
+func writeArityError(w *bufio.Writer, command string) {
+	writeError(w, "wrong number of arguments for '"+strings.ToLower(command)+"' command")
+}
@@ -0,0 +1,219 @@
+package resp
+
+import (
+	"bufio"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"testing"
+	"uidGenerator/generator"
+	"uidGenerator/timeprovider/epoch"
+)
+
+// rawClient Speaks just enough RESP2 to check the server replies
+type rawClient struct {
+	conn net.Conn
+	r    *bufio.Reader
+}
+
+func newRawClient(t *testing.T) *rawClient {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	go Serve(listener, generator.NewPool(2, epoch.New(1420070400000)))
+	t.Cleanup(func() { listener.Close() })
+
+	conn, err := net.Dial("tcp", listener.Addr().String())
+	if err != nil {
+		t.Fatalf("Failed to dial: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return &rawClient{conn: conn, r: bufio.NewReader(conn)}
+}
+
+func (c *rawClient) send(t *testing.T, args ...string) {
+	var b strings.Builder
+	b.WriteString("*" + strconv.Itoa(len(args)) + "\r\n")
+	for _, arg := range args {
+		b.WriteString("$" + strconv.Itoa(len(arg)) + "\r\n" + arg + "\r\n")
+	}
+	if _, err := c.conn.Write([]byte(b.String())); err != nil {
+		t.Fatalf("Failed to send command: %v", err)
+	}
+}
+
+// reply Reads one reply, arrays are returned as []interface{}
+func (c *rawClient) reply(t *testing.T) interface{} {
+	line, err := c.r.ReadString('\n')
+	if err != nil {
+		t.Fatalf("Failed to read reply: %v", err)
+	}
+	line = strings.TrimSuffix(line, "\r\n")
+	switch line[0] {
+	case '+':
+		return line[1:]
+	case '-':
+		return replyError(line[1:])
+	case ':':
+		v, err := strconv.ParseInt(line[1:], 10, 64)
+		if err != nil {
+			t.Fatalf("Invalid integer reply %q", line)
+		}
+		return v
+	case '$':
+		n, _ := strconv.Atoi(line[1:])
+		buf := make([]byte, n+2)
+		if _, err := io.ReadFull(c.r, buf); err != nil {
+			t.Fatalf("Failed to read bulk string: %v", err)
+		}
+		return string(buf[:n])
+	case '*':
+		n, _ := strconv.Atoi(line[1:])
+		array := make([]interface{}, n)
+		for i := range array {
+			array[i] = c.reply(t)
+		}
+		return array
+	}
+	t.Fatalf("Unexpected reply %q", line)
+	return nil
+}
+
+type replyError string
+
+func TestServe_Next(t *testing.T) {
+	c := newRawClient(t)
+	c.send(t, "IDGEN.NEXT")
+
+	id, ok := c.reply(t).(int64)
+	if !ok || id <= 0 {
+		t.Errorf("Expected positive integer reply, got %v", id)
+	}
+}
+
+func TestServe_Batch(t *testing.T) {
+	c := newRawClient(t)
+	c.send(t, "idgen.batch", "25")
+
+	ids, ok := c.reply(t).([]interface{})
+	if !ok || len(ids) != 25 {
+		t.Fatalf("Expected array of 25 IDs, got %v", ids)
+	}
+	idMap := make(map[int64]bool)
+	for _, id := range ids {
+		if idMap[id.(int64)] {
+			t.Errorf("Duplicate ID found: %d", id)
+		}
+		idMap[id.(int64)] = true
+	}
+}
+
+func TestServe_Decode(t *testing.T) {
+	c := newRawClient(t)
+	c.send(t, "IDGEN.NEXT")
+	id := c.reply(t).(int64)
+
+	c.send(t, "IDGEN.DECODE", strconv.FormatInt(id, 10))
+	fields, ok := c.reply(t).([]interface{})
+	if !ok || len(fields) != 8 {
+		t.Fatalf("Expected 8 elements, got %v", fields)
+	}
+
+	components := generator.Decode(id)
+	expected := []interface{}{
+		"timestamp", components.Timestamp,
+		"workerId", int64(2),
+		"threadId", components.ThreadId,
+		"counter", components.Counter,
+	}
+	for i := range expected {
+		if fields[i] != expected[i] {
+			t.Errorf("Element %d: expected %v, got %v", i, expected[i], fields[i])
+		}
+	}
+}
+
+func TestServe_Pipelined(t *testing.T) {
+	c := newRawClient(t)
+	for i := 0; i < 10; i++ {
+		c.send(t, "IDGEN.NEXT")
+	}
+
+	var last int64
+	for i := 0; i < 10; i++ {
+		id := c.reply(t).(int64)
+		if id == last {
+			t.Errorf("Duplicate ID found: %d", id)
+		}
+		last = id
+	}
+}
+
+func TestServe_InlineCommand(t *testing.T) {
+	c := newRawClient(t)
+	if _, err := c.conn.Write([]byte("PING\r\nIDGEN.BATCH 2\r\n")); err != nil {
+		t.Fatalf("Failed to send command: %v", err)
+	}
+
+	if reply := c.reply(t); reply != "PONG" {
+		t.Errorf("Expected PONG, got %v", reply)
+	}
+	if ids, ok := c.reply(t).([]interface{}); !ok || len(ids) != 2 {
+		t.Errorf("Expected 2 IDs, got %v", ids)
+	}
+}
+
+func TestServe_Errors(t *testing.T) {
+	testCases := []struct {
+		name string
+		args []string
+	}{
+		{"Unknown command", []string{"GET", "key"}},
+		{"Wrong arity", []string{"IDGEN.BATCH"}},
+		{"Invalid batch size", []string{"IDGEN.BATCH", "zero"}},
+		{"Batch too large", []string{"IDGEN.BATCH", strconv.Itoa(MaxBatch + 1)}},
+		{"Invalid ID", []string{"IDGEN.DECODE", "abc"}},
+	}
+
+	c := newRawClient(t)
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			c.send(t, tc.args...)
+			if _, ok := c.reply(t).(replyError); !ok {
+				t.Error("Expected error reply")
+			}
+		})
+	}
+
+	// Errors leave the connection usable
+	c.send(t, "PING", "hello")
+	if reply := c.reply(t); reply != "hello" {
+		t.Errorf("Expected hello, got %v", reply)
+	}
+}
+
+func TestServe_UnknownCommandWithLineBreaks(t *testing.T) {
+	c := newRawClient(t)
+	c.send(t, "FOO\r\n:42\r\n")
+	c.send(t, "PING")
+
+	if reply, ok := c.reply(t).(replyError); !ok || reply != "ERR unknown command 'FOO  :42  '" {
+		t.Errorf("Expected a single error reply, got %v", reply)
+	}
+	if reply := c.reply(t); reply != "PONG" {
+		t.Errorf("Expected PONG, got %v", reply)
+	}
+}
+
+func TestServe_Quit(t *testing.T) {
+	c := newRawClient(t)
+	c.send(t, "QUIT")
+	if reply := c.reply(t); reply != "OK" {
+		t.Errorf("Expected OK, got %v", reply)
+	}
+	if _, err := c.r.ReadByte(); err == nil {
+		t.Error("Expected connection to be closed")
+	}
+}
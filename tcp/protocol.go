@@ -0,0 +1,67 @@
+package tcp
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+)
+
+// Every frame is a 4 byte big-endian payload length followed by the payload.
+//
+// A request payload is the number of IDs as a 4 byte big-endian integer.
+// A response payload starts with a status byte: on StatusOK it is followed by
+// the IDs as 8 byte big-endian integers, otherwise by an error message.
+//
+// Responses are written in request order, so clients may pipeline requests.
+const (
+	StatusOK byte = iota
+	StatusBadRequest
+	StatusGenerationFailed
+)
+
+// MaxBatch is the largest number of IDs a single request may ask for
+const MaxBatch = 1 << 16
+
+// MaxFrameSize bounds a response frame: status byte plus MaxBatch IDs
+const MaxFrameSize = 1 + MaxBatch*8
+
+var ErrFrameTooLarge = errors.New("frame too large")
+
+// StatusError Is returned by clients when the server answered with an error status
+type StatusError struct {
+	Status  byte
+	Message string
+}
+
+func (e *StatusError) Error() string {
+	return e.Message
+}
+
+// AppendFrame Appends payload to dst with its length prefix
+func AppendFrame(dst []byte, payload []byte) []byte {
+	dst = binary.BigEndian.AppendUint32(dst, uint32(len(payload)))
+	return append(dst, payload...)
+}
+
+// ReadFrame Reads the next frame into buf, growing it when needed
+func ReadFrame(r io.Reader, buf []byte) ([]byte, error) {
+	var header [4]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return nil, err
+	}
+	size := binary.BigEndian.Uint32(header[:])
+	if size > MaxFrameSize {
+		return nil, ErrFrameTooLarge
+	}
+	if uint32(cap(buf)) < size {
+		buf = make([]byte, size)
+	}
+	buf = buf[:size]
+	if _, err := io.ReadFull(r, buf); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return nil, err
+	}
+	return buf, nil
+}
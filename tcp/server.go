@@ -0,0 +1,78 @@
+package tcp
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"net"
+	"uidGenerator/generator"
+)
+
+// Serve Accepts connections on l and answers their requests with IDs from
+// pool until l is closed
+func Serve(l net.Listener, pool *generator.Pool) error {
+	for {
+		conn, err := l.Accept()
+		if err != nil {
+			if errors.Is(err, net.ErrClosed) {
+				return nil
+			}
+			return err
+		}
+		go serveConn(conn, pool)
+	}
+}
+
+func serveConn(conn net.Conn, pool *generator.Pool) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	w := bufio.NewWriter(conn)
+
+	var request, response []byte
+	for {
+		var err error
+		request, err = ReadFrame(r, request)
+		if err != nil {
+			return
+		}
+
+		response = handle(response[:0], request, pool)
+		if _, err := w.Write(response); err != nil {
+			return
+		}
+
+		// Pipelined requests are answered in one write once the input runs dry
+		if r.Buffered() == 0 {
+			if err := w.Flush(); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// handle Appends the response frame for one request payload to dst
+func handle(dst []byte, request []byte, pool *generator.Pool) []byte {
+	if len(request) != 4 {
+		return appendError(dst, StatusBadRequest, "invalid request")
+	}
+	count := binary.BigEndian.Uint32(request)
+	if count > MaxBatch {
+		return appendError(dst, StatusBadRequest, "batch too large")
+	}
+
+	ids, err := pool.GenerateID(int(count))
+	if err != nil {
+		return appendError(dst, StatusGenerationFailed, err.Error())
+	}
+
+	dst = binary.BigEndian.AppendUint32(dst, uint32(1+len(ids)*8))
+	dst = append(dst, StatusOK)
+	for _, id := range ids {
+		dst = binary.BigEndian.AppendUint64(dst, uint64(id))
+	}
+	return dst
+}
+
+func appendError(dst []byte, status byte, message string) []byte {
+	return AppendFrame(dst, append([]byte{status}, message...))
+}
@@ -0,0 +1,128 @@
+package tcp
+
+import (
+	"bytes"
+	"encoding/binary"
+	"net"
+	"testing"
+	"uidGenerator/generator"
+	"uidGenerator/timeprovider/epoch"
+)
+
+func startServer(t *testing.T) net.Conn {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+	pool := generator.NewPool(1, epoch.New(1420070400000))
+	go Serve(listener, pool)
+	t.Cleanup(func() { listener.Close() })
+
+	conn, err := net.Dial("tcp", listener.Addr().String())
+	if err != nil {
+		t.Fatalf("Failed to dial: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+func request(count uint32) []byte {
+	return AppendFrame(nil, binary.BigEndian.AppendUint32(nil, count))
+}
+
+func TestServe_Batch(t *testing.T) {
+	conn := startServer(t)
+
+	if _, err := conn.Write(request(5)); err != nil {
+		t.Fatalf("Failed to write request: %v", err)
+	}
+
+	frame, err := ReadFrame(conn, nil)
+	if err != nil {
+		t.Fatalf("Failed to read response: %v", err)
+	}
+	if frame[0] != StatusOK {
+		t.Fatalf("Expected StatusOK, got %d: %s", frame[0], frame[1:])
+	}
+	if len(frame) != 1+5*8 {
+		t.Errorf("Expected 5 IDs, got %d bytes", len(frame)-1)
+	}
+}
+
+func TestServe_Pipelined(t *testing.T) {
+	conn := startServer(t)
+
+	var requests []byte
+	for i := 1; i <= 10; i++ {
+		requests = append(requests, request(uint32(i))...)
+	}
+	if _, err := conn.Write(requests); err != nil {
+		t.Fatalf("Failed to write requests: %v", err)
+	}
+
+	var last uint64
+	for i := 1; i <= 10; i++ {
+		frame, err := ReadFrame(conn, nil)
+		if err != nil {
+			t.Fatalf("Failed to read response %d: %v", i, err)
+		}
+		if frame[0] != StatusOK {
+			t.Fatalf("Expected StatusOK, got %d", frame[0])
+		}
+
+		// Responses arrive in request order
+		if len(frame) != 1+i*8 {
+			t.Errorf("Response %d: expected %d IDs, got %d bytes", i, i, len(frame)-1)
+		}
+		for j := 1; j < len(frame); j += 8 {
+			id := binary.BigEndian.Uint64(frame[j:])
+			if id == last {
+				t.Errorf("Duplicate ID found: %d", id)
+			}
+			last = id
+		}
+	}
+}
+
+func TestServe_BadRequest(t *testing.T) {
+	testCases := []struct {
+		name    string
+		request []byte
+	}{
+		{"Short payload", AppendFrame(nil, []byte{1})},
+		{"Batch too large", request(MaxBatch + 1)},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			conn := startServer(t)
+			if _, err := conn.Write(tc.request); err != nil {
+				t.Fatalf("Failed to write request: %v", err)
+			}
+
+			frame, err := ReadFrame(conn, nil)
+			if err != nil {
+				t.Fatalf("Failed to read response: %v", err)
+			}
+			if frame[0] != StatusBadRequest {
+				t.Errorf("Expected StatusBadRequest, got %d", frame[0])
+			}
+
+			// The connection stays usable
+			if _, err := conn.Write(request(1)); err != nil {
+				t.Fatalf("Failed to write request: %v", err)
+			}
+			frame, err = ReadFrame(conn, nil)
+			if err != nil || frame[0] != StatusOK {
+				t.Errorf("Expected connection to recover, got %v", err)
+			}
+		})
+	}
+}
+
+func TestReadFrame_TooLarge(t *testing.T) {
+	header := binary.BigEndian.AppendUint32(nil, MaxFrameSize+1)
+	if _, err := ReadFrame(bytes.NewReader(header), nil); err != ErrFrameTooLarge {
+		t.Errorf("Expected ErrFrameTooLarge, got %v", err)
+	}
+}
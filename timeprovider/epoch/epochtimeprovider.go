@@ -1,20 +1,79 @@
 package epoch
 
-import "time"
+import (
+	"math"
+	"time"
+)
 
 // TimeProvider Implements the TimeProvider interface
 type timeProvider struct {
 	epochOffset int64
+	tick        int64 // Length of a timestamp unit in milliseconds
 }
 
-// New TimeProvider based on provided epoch
-func New(epochOffset int64) *timeProvider {
-	return &timeProvider{
+// Option Configures the epoch TimeProvider
+type Option func(*timeProvider)
+
+// WithTick Counts time in units of tick instead of milliseconds, e.g. 10ms
+// like Sonyflake. Longer ticks make the timestamp bits last longer but give
+// every thread the same number of IDs per tick. The tick is rounded down to
+// whole milliseconds, and is at least one.
+func WithTick(tick time.Duration) Option {
+	return func(t *timeProvider) {
+		t.tick = max(tick.Milliseconds(), 1)
+	}
+}
+
+// New TimeProvider based on provided epoch, in milliseconds
+func New(epochOffset int64, opts ...Option) *timeProvider {
+	t := &timeProvider{
 		epochOffset: epochOffset,
+		tick:        1,
 	}
+	for _, opt := range opts {
+		opt(t)
+	}
+	return t
 }
 
-// GetTimeStamp Returns the current time in milliseconds
+// GetTimeStamp Returns the current time in ticks, milliseconds by default
 func (t *timeProvider) GetTimeStamp() int64 {
-	return time.Now().UTC().UnixMilli() - t.epochOffset
+	return (time.Now().UTC().UnixMilli() - t.epochOffset) / t.tick
+}
+
+// Tick Returns the length of a timestamp unit
+func (t *timeProvider) Tick() time.Duration {
+	return time.Duration(t.tick) * time.Millisecond
+}
+
+// Decode Returns the beginning of the tick a timestamp was taken in
+func (t *timeProvider) Decode(timestamp int64) time.Time {
+	return time.UnixMilli(t.epochOffset + timestamp*t.tick).UTC()
+}
+
+// millisecondsPerYear Is the average length of a Gregorian year
+const millisecondsPerYear = 365.2425 * 24 * 60 * 60 * 1000
+
+// Capacity Describes how long and how fast IDs can be issued with a provider
+type Capacity struct {
+	Tick         time.Duration // Length of a timestamp unit
+	Years        float64       // Time until the timestamp bits are exhausted
+	Exhausted    time.Time     // Moment the timestamp bits are exhausted
+	IDsPerTick   int64         // IDs one thread can issue per tick
+	IDsPerSecond float64       // IDs one thread can issue per second
+}
+
+// Capacity Returns the capacity of IDs with the given numbers of timestamp
+// and counter bits
+func (t *timeProvider) Capacity(timestampBits, counterBits int64) Capacity {
+	ticks := math.Exp2(float64(timestampBits))
+	milliseconds := ticks * float64(t.tick)
+	idsPerTick := int64(1) << counterBits
+	return Capacity{
+		Tick:         t.Tick(),
+		Years:        milliseconds / millisecondsPerYear,
+		Exhausted:    t.Decode(int64(ticks)),
+		IDsPerTick:   idsPerTick,
+		IDsPerSecond: float64(idsPerTick) * 1000 / float64(t.tick),
+	}
 }
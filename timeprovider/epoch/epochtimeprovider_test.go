@@ -57,3 +57,83 @@ func TestGetTimeStamp_WithOffset(t *testing.T) {
 		t.Errorf("Expected timestamp to be around %d, got %d", expectedApprox, timestamp)
 	}
 }
+
+func TestGetTimeStamp_WithTick(t *testing.T) {
+	offset := int64(1420070400000)
+	milliseconds := New(offset)
+	centiseconds := New(offset, WithTick(10*time.Millisecond))
+
+	before := milliseconds.GetTimeStamp()
+	timestamp := centiseconds.GetTimeStamp()
+	after := milliseconds.GetTimeStamp()
+
+	if timestamp < before/10 || timestamp > after/10 {
+		t.Errorf("Expected %d to be between %d and %d", timestamp, before/10, after/10)
+	}
+}
+
+func TestWithTick_RoundsToMilliseconds(t *testing.T) {
+	testCases := []struct {
+		tick     time.Duration
+		expected time.Duration
+	}{
+		{10 * time.Millisecond, 10 * time.Millisecond},
+		{time.Second, time.Second},
+		{1500 * time.Microsecond, time.Millisecond},
+		{time.Microsecond, time.Millisecond},
+		{0, time.Millisecond},
+	}
+
+	for _, tc := range testCases {
+		provider := New(0, WithTick(tc.tick))
+		if provider.Tick() != tc.expected {
+			t.Errorf("Expected tick %v for %v, got %v", tc.expected, tc.tick, provider.Tick())
+		}
+	}
+}
+
+func TestDecode(t *testing.T) {
+	offset := int64(1420070400000)
+	provider := New(offset, WithTick(10*time.Millisecond))
+
+	expected := time.Date(2024, 5, 6, 7, 8, 9, 120000000, time.UTC)
+	timestamp := (expected.UnixMilli() - offset) / 10
+	if decoded := provider.Decode(timestamp); !decoded.Equal(expected) {
+		t.Errorf("Expected %v, got %v", expected, decoded)
+	}
+
+	// A timestamp covers its whole tick
+	if decoded := provider.Decode(provider.GetTimeStamp()); time.Since(decoded) < 0 || time.Since(decoded) > time.Second {
+		t.Errorf("Expected %v to be within a second before now", decoded)
+	}
+}
+
+func TestCapacity(t *testing.T) {
+	offset := int64(1420070400000)
+	testCases := []struct {
+		tick         time.Duration
+		years        int
+		exhausted    int
+		idsPerSecond float64
+	}{
+		{time.Millisecond, 69, 2084, 1024000},
+		{10 * time.Millisecond, 696, 2711, 102400},
+		{time.Second, 69684, 71699, 1024},
+	}
+
+	for _, tc := range testCases {
+		capacity := New(offset, WithTick(tc.tick)).Capacity(41, 10)
+		if int(capacity.Years) != tc.years {
+			t.Errorf("Expected %d years for %v, got %f", tc.years, tc.tick, capacity.Years)
+		}
+		if capacity.Exhausted.Year() != tc.exhausted {
+			t.Errorf("Expected exhaustion in %d for %v, got %v", tc.exhausted, tc.tick, capacity.Exhausted)
+		}
+		if capacity.IDsPerTick != 1024 {
+			t.Errorf("Expected 1024 IDs per tick, got %d", capacity.IDsPerTick)
+		}
+		if capacity.IDsPerSecond != tc.idsPerSecond {
+			t.Errorf("Expected %f IDs per second for %v, got %f", tc.idsPerSecond, tc.tick, capacity.IDsPerSecond)
+		}
+	}
+}
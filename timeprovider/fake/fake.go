@@ -0,0 +1,148 @@
+// Package fake provides time providers for deterministic tests of code using
+// the generator, such as counter rollover and clocks going backwards.
+package fake
+
+import (
+	"sync"
+	"sync/atomic"
+	"uidGenerator/timeprovider"
+)
+
+// Clock Returns a timestamp the test sets and advances explicitly. It is safe
+// for concurrent use.
+type Clock struct {
+	now atomic.Int64
+}
+
+// NewClock Creates a Clock starting at the given timestamp
+func NewClock(start int64) *Clock {
+	c := &Clock{}
+	c.now.Store(start)
+	return c
+}
+
+// GetTimeStamp Returns the current timestamp of the clock
+func (c *Clock) GetTimeStamp() int64 {
+	return c.now.Load()
+}
+
+// Set Moves the clock to timestamp, which may be in the past
+func (c *Clock) Set(timestamp int64) {
+	c.now.Store(timestamp)
+}
+
+// Advance Moves the clock by delta, which may be negative, and returns the
+// new timestamp
+func (c *Clock) Advance(delta int64) int64 {
+	return c.now.Add(delta)
+}
+
+// Sequence Returns scripted timestamps in order and then keeps repeating the
+// last one. Timestamps may go backwards to script clock regressions. It is
+// safe for concurrent use.
+type Sequence struct {
+	mutex      sync.Mutex
+	timestamps []int64
+	calls      int
+}
+
+// NewSequence Creates a Sequence returning the given timestamps, at least
+// one is required
+func NewSequence(timestamps ...int64) *Sequence {
+	if len(timestamps) == 0 {
+		panic("fake: NewSequence needs at least one timestamp")
+	}
+	return &Sequence{timestamps: timestamps}
+}
+
+// GetTimeStamp Returns the next scripted timestamp
+func (s *Sequence) GetTimeStamp() int64 {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	i := min(s.calls, len(s.timestamps)-1)
+	s.calls++
+	return s.timestamps[i]
+}
+
+// Calls Returns how often GetTimeStamp was called
+func (s *Sequence) Calls() int {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.calls
+}
+
+// Skewed Wraps a provider and shifts its timestamps by an offset, plus a drift
+// that grows with the time elapsed since the first call, like a clock that
+// runs fast or slow. The offset can be changed to script clock steps. It is
+// safe for concurrent use when the wrapped provider is.
+type Skewed struct {
+	base   timeprovider.TimeProvider
+	mutex  sync.Mutex
+	offset int64
+	rate   float64
+	start  int64
+	called bool
+}
+
+// NewSkewed Creates a Skewed clock adding offset to base, and drifting by
+// rate per unit of base time, e.g. 0.001 for 1 ms per second
+func NewSkewed(base timeprovider.TimeProvider, offset int64, rate float64) *Skewed {
+	return &Skewed{
+		base:   base,
+		offset: offset,
+		rate:   rate,
+	}
+}
+
+// GetTimeStamp Returns the skewed timestamp of the wrapped provider
+func (s *Skewed) GetTimeStamp() int64 {
+	now := s.base.GetTimeStamp()
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	if !s.called {
+		s.called = true
+		s.start = now
+	}
+	return now + s.offset + int64(float64(now-s.start)*s.rate)
+}
+
+// SetOffset Changes the offset, stepping the clock forward or backwards
+func (s *Skewed) SetOffset(offset int64) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.offset = offset
+}
+
+// Fallible Wraps a provider as a FallibleTimeProvider whose reads fail while
+// an error is set. It is safe for concurrent use when the wrapped provider is.
+type Fallible struct {
+	base  timeprovider.TimeProvider
+	mutex sync.Mutex
+	err   error
+}
+
+// NewFallible Creates a Fallible clock reading base
+func NewFallible(base timeprovider.TimeProvider) *Fallible {
+	return &Fallible{base: base}
+}
+
+// GetTimeStamp Returns the timestamp of the wrapped provider, or the error
+// when one is set
+func (f *Fallible) GetTimeStamp() (int64, error) {
+	f.mutex.Lock()
+	err := f.err
+	f.mutex.Unlock()
+	if err != nil {
+		return 0, err
+	}
+	return f.base.GetTimeStamp(), nil
+}
+
+// SetError Makes every read fail with err, or succeed again when it is nil
+func (f *Fallible) SetError(err error) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.err = err
+}
@@ -0,0 +1,118 @@
+package fake
+
+import (
+	"errors"
+	"sync"
+	"testing"
+)
+
+func TestClock(t *testing.T) {
+	clock := NewClock(100)
+
+	if timestamp := clock.GetTimeStamp(); timestamp != 100 {
+		t.Errorf("Expected 100, got %d", timestamp)
+	}
+	if timestamp := clock.Advance(5); timestamp != 105 {
+		t.Errorf("Expected 105, got %d", timestamp)
+	}
+	clock.Set(90)
+	if timestamp := clock.GetTimeStamp(); timestamp != 90 {
+		t.Errorf("Expected 90, got %d", timestamp)
+	}
+	if timestamp := clock.Advance(-10); timestamp != 80 {
+		t.Errorf("Expected 80, got %d", timestamp)
+	}
+}
+
+func TestClock_Concurrent(t *testing.T) {
+	clock := NewClock(0)
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				clock.Advance(1)
+				clock.GetTimeStamp()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if timestamp := clock.GetTimeStamp(); timestamp != 1000 {
+		t.Errorf("Expected 1000, got %d", timestamp)
+	}
+}
+
+func TestSequence(t *testing.T) {
+	sequence := NewSequence(100, 98, 101)
+
+	expected := []int64{100, 98, 101, 101, 101}
+	for i, want := range expected {
+		if timestamp := sequence.GetTimeStamp(); timestamp != want {
+			t.Errorf("Expected %d at call %d, got %d", want, i, timestamp)
+		}
+	}
+	if calls := sequence.Calls(); calls != len(expected) {
+		t.Errorf("Expected %d calls, got %d", len(expected), calls)
+	}
+}
+
+func TestNewSequence_Empty(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected a panic for an empty sequence")
+		}
+	}()
+	NewSequence()
+}
+
+func TestSkewed(t *testing.T) {
+	base := NewClock(1000)
+	skewed := NewSkewed(base, 50, 0.5)
+
+	if timestamp := skewed.GetTimeStamp(); timestamp != 1050 {
+		t.Errorf("Expected 1050, got %d", timestamp)
+	}
+
+	base.Advance(100)
+	if timestamp := skewed.GetTimeStamp(); timestamp != 1200 {
+		t.Errorf("Expected 1200 after drifting 50, got %d", timestamp)
+	}
+
+	// A step back of the skewed clock
+	skewed.SetOffset(-100)
+	if timestamp := skewed.GetTimeStamp(); timestamp != 1050 {
+		t.Errorf("Expected 1050, got %d", timestamp)
+	}
+}
+
+func TestSkewed_SlowClock(t *testing.T) {
+	base := NewClock(0)
+	skewed := NewSkewed(base, 0, -0.001)
+	skewed.GetTimeStamp()
+
+	base.Set(10000)
+	if timestamp := skewed.GetTimeStamp(); timestamp != 9990 {
+		t.Errorf("Expected 9990, got %d", timestamp)
+	}
+}
+
+func TestFallible(t *testing.T) {
+	clock := NewFallible(NewClock(100))
+
+	if timestamp, err := clock.GetTimeStamp(); timestamp != 100 || err != nil {
+		t.Errorf("Expected 100 without error, got %d and %v", timestamp, err)
+	}
+
+	broken := errors.New("broken")
+	clock.SetError(broken)
+	if _, err := clock.GetTimeStamp(); err != broken {
+		t.Errorf("Expected the error, got %v", err)
+	}
+
+	clock.SetError(nil)
+	if _, err := clock.GetTimeStamp(); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+}
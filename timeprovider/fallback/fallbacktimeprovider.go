@@ -0,0 +1,129 @@
+package fallback
+
+import (
+	"log"
+	"sync"
+	"time"
+)
+
+// DefaultMaxDrift is the drift between the sources that is logged
+const DefaultMaxDrift = 100 * time.Millisecond
+
+// Source Is a preferred time source that can tell when it is not to be
+// trusted
+type Source interface {
+	// Read Returns the time of the source in Unix milliseconds, given the
+	// system clock, or an error when the source is unavailable or stale
+	Read(now time.Time) (int64, error)
+}
+
+// Status Describes which source the provider uses
+type Status struct {
+	Primary   bool          // Whether the primary source is in use
+	Drift     time.Duration // Last measured lead of the primary over the system clock
+	Fallbacks int64         // How often the provider fell back to the system clock
+	Err       error         // Why the primary source is not in use
+}
+
+// TimeProvider Implements the TimeProvider interface on a primary source,
+// such as a time file written by a PTP or chrony daemon, falling back to the
+// system clock while the primary fails. The drift between both is measured
+// on every read; during a fallback it is added to the system clock, so
+// switching sources does not make timestamps jump back. When the primary
+// recovers with a smaller lead, the timestamp holds until it catches up.
+type timeProvider struct {
+	epochOffset int64
+	primary     Source
+	maxDrift    time.Duration
+	logf        func(format string, args ...interface{})
+	now         func() time.Time
+
+	mutex       sync.Mutex
+	status      Status
+	driftLogged bool
+	last        int64 // Latest timestamp returned
+}
+
+// Option Configures the fallback TimeProvider
+type Option func(*timeProvider)
+
+// WithMaxDrift Logs when the sources drift apart by more than maxDrift,
+// DefaultMaxDrift by default
+func WithMaxDrift(maxDrift time.Duration) Option {
+	return func(t *timeProvider) {
+		t.maxDrift = maxDrift
+	}
+}
+
+// WithLogger Replaces log.Printf for reporting fallbacks and drift
+func WithLogger(logf func(format string, args ...interface{})) Option {
+	return func(t *timeProvider) {
+		t.logf = logf
+	}
+}
+
+// New TimeProvider based on provided epoch, in milliseconds, reading primary
+// first
+func New(epochOffset int64, primary Source, opts ...Option) *timeProvider {
+	t := &timeProvider{
+		epochOffset: epochOffset,
+		primary:     primary,
+		maxDrift:    DefaultMaxDrift,
+		logf:        log.Printf,
+		now:         time.Now,
+		status:      Status{Primary: true},
+	}
+	for _, opt := range opts {
+		opt(t)
+	}
+	return t
+}
+
+// GetTimeStamp Returns the time of the primary source in milliseconds, or
+// the drift corrected system clock when it fails, never less than the time
+// returned before
+func (t *timeProvider) GetTimeStamp() int64 {
+	now := t.now()
+	system := now.UnixMilli()
+	primary, err := t.primary.Read(now)
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if err != nil {
+		if t.status.Primary {
+			t.status.Fallbacks++
+			t.logf("Primary time source failed, falling back to the system clock with a drift of %v: %v", t.status.Drift, err)
+		}
+		t.status.Primary = false
+		t.status.Err = err
+		return t.advance(system + t.status.Drift.Milliseconds() - t.epochOffset)
+	}
+
+	if !t.status.Primary {
+		t.logf("Primary time source recovered")
+	}
+	t.status.Primary = true
+	t.status.Err = nil
+	t.status.Drift = time.Duration(primary-system) * time.Millisecond
+
+	exceeded := t.maxDrift > 0 && t.status.Drift.Abs() > t.maxDrift
+	if exceeded && !t.driftLogged {
+		t.logf("Primary time source drifted %v from the system clock", t.status.Drift)
+	}
+	t.driftLogged = exceeded
+	return t.advance(primary - t.epochOffset)
+}
+
+// advance Returns timestamp, or the latest one returned when that is later
+func (t *timeProvider) advance(timestamp int64) int64 {
+	t.last = max(t.last, timestamp)
+	return t.last
+}
+
+// Status Returns which source is in use and the drift between them
+func (t *timeProvider) Status() Status {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	return t.status
+}
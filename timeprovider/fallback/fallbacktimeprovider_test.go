@@ -0,0 +1,144 @@
+package fallback
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+var errUnavailable = errors.New("unavailable")
+
+// stubSource Returns a fixed lead over the system clock, or an error
+type stubSource struct {
+	lead time.Duration
+	err  error
+}
+
+func (s *stubSource) Read(now time.Time) (int64, error) {
+	if s.err != nil {
+		return 0, s.err
+	}
+	return now.Add(s.lead).UnixMilli(), nil
+}
+
+func newTestProvider(source Source, now *time.Time, logs *[]string, opts ...Option) *timeProvider {
+	opts = append(opts, WithLogger(func(format string, args ...interface{}) {
+		*logs = append(*logs, fmt.Sprintf(format, args...))
+	}))
+	provider := New(0, source, opts...)
+	provider.now = func() time.Time { return *now }
+	return provider
+}
+
+func TestGetTimeStamp_Primary(t *testing.T) {
+	now := time.UnixMilli(1_000_000)
+	var logs []string
+	provider := newTestProvider(&stubSource{lead: 20 * time.Millisecond}, &now, &logs)
+
+	if timestamp := provider.GetTimeStamp(); timestamp != 1_000_020 {
+		t.Errorf("Expected 1000020, got %d", timestamp)
+	}
+	status := provider.Status()
+	if !status.Primary || status.Drift != 20*time.Millisecond || status.Err != nil {
+		t.Errorf("Expected the primary with a drift of 20ms, got %+v", status)
+	}
+	if len(logs) != 0 {
+		t.Errorf("Expected nothing logged, got %v", logs)
+	}
+}
+
+func TestGetTimeStamp_FallsBackWithDrift(t *testing.T) {
+	now := time.UnixMilli(1_000_000)
+	var logs []string
+	source := &stubSource{lead: 50 * time.Millisecond}
+	provider := newTestProvider(source, &now, &logs)
+
+	previous := provider.GetTimeStamp()
+
+	// The primary fails, the system clock continues with the last drift
+	source.err = errUnavailable
+	for i := 0; i < 3; i++ {
+		now = now.Add(time.Millisecond)
+		timestamp := provider.GetTimeStamp()
+		if timestamp != previous+1 {
+			t.Errorf("Expected %d, got %d", previous+1, timestamp)
+		}
+		previous = timestamp
+	}
+	status := provider.Status()
+	if status.Primary || status.Fallbacks != 1 || !errors.Is(status.Err, errUnavailable) {
+		t.Errorf("Expected one fallback to the system clock, got %+v", status)
+	}
+
+	// The primary recovers
+	source.err = nil
+	now = now.Add(time.Millisecond)
+	if timestamp := provider.GetTimeStamp(); timestamp != previous+1 {
+		t.Errorf("Expected %d, got %d", previous+1, timestamp)
+	}
+	if !provider.Status().Primary {
+		t.Error("Expected the primary to be used again")
+	}
+	if len(logs) != 2 {
+		t.Errorf("Expected the fallback and recovery logged, got %v", logs)
+	}
+}
+
+func TestGetTimeStamp_RecoversWithSmallerLead(t *testing.T) {
+	now := time.UnixMilli(1_000_000)
+	var logs []string
+	source := &stubSource{lead: 500 * time.Millisecond}
+	provider := newTestProvider(source, &now, &logs)
+	provider.GetTimeStamp()
+
+	// The primary fails and comes back without its lead
+	source.err = errUnavailable
+	now = now.Add(100 * time.Millisecond)
+	held := provider.GetTimeStamp()
+	if held != 1_000_600 {
+		t.Errorf("Expected 1000600, got %d", held)
+	}
+	source.err = nil
+	source.lead = 0
+
+	// The timestamp holds until the primary catches up
+	for _, step := range []time.Duration{time.Millisecond, 400 * time.Millisecond, 98 * time.Millisecond} {
+		now = now.Add(step)
+		if timestamp := provider.GetTimeStamp(); timestamp != held {
+			t.Errorf("Expected %d to be held, got %d", held, timestamp)
+		}
+	}
+	now = now.Add(2 * time.Millisecond)
+	if timestamp := provider.GetTimeStamp(); timestamp != held+1 {
+		t.Errorf("Expected %d, got %d", held+1, timestamp)
+	}
+	if drift := provider.Status().Drift; drift != 0 {
+		t.Errorf("Expected a drift of 0, got %v", drift)
+	}
+}
+
+func TestGetTimeStamp_LogsDriftOnce(t *testing.T) {
+	now := time.UnixMilli(1_000_000)
+	var logs []string
+	source := &stubSource{lead: 300 * time.Millisecond}
+	provider := newTestProvider(source, &now, &logs, WithMaxDrift(200*time.Millisecond))
+
+	provider.GetTimeStamp()
+	provider.GetTimeStamp()
+	if len(logs) != 1 {
+		t.Errorf("Expected the drift logged once, got %v", logs)
+	}
+
+	// Back within bounds and out again
+	source.lead = 0
+	provider.GetTimeStamp()
+	source.lead = -250 * time.Millisecond
+	provider.GetTimeStamp()
+	if len(logs) != 2 {
+		t.Errorf("Expected the drift logged again, got %v", logs)
+	}
+	if drift := provider.Status().Drift; drift != -250*time.Millisecond {
+		t.Errorf("Expected a drift of -250ms, got %v", drift)
+	}
+}
@@ -0,0 +1,77 @@
+package fallback
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// DefaultRefresh is how long a FileSource reuses the last read of its file
+const DefaultRefresh = 10 * time.Millisecond
+
+var ErrStale = errors.New("time file is stale")
+
+// FileSource Reads the time from a file a local daemon rewrites regularly
+// with the current Unix time in milliseconds. The time of the source is the
+// time in the file plus the time since the file was written, which makes the
+// lead of the file over the system clock the drift. The file is considered
+// stale when it was not rewritten within maxAge.
+type FileSource struct {
+	path    string
+	maxAge  time.Duration
+	refresh time.Duration
+
+	mutex    sync.Mutex
+	readAt   time.Time // System clock of the last read of the file
+	written  time.Time // Modification time of the file
+	contents int64     // Time in the file
+	err      error     // Error of the last read
+}
+
+// NewFileSource Creates a source reading the file at path
+func NewFileSource(path string, maxAge time.Duration) *FileSource {
+	return &FileSource{
+		path:    path,
+		maxAge:  maxAge,
+		refresh: DefaultRefresh,
+	}
+}
+
+// Read Returns the time of the file, re-reading it at most every
+// DefaultRefresh
+func (s *FileSource) Read(now time.Time) (int64, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.readAt.IsZero() || now.Sub(s.readAt) >= s.refresh || now.Before(s.readAt) {
+		s.readAt = now
+		s.written, s.contents, s.err = readTimeFile(s.path)
+	}
+	if s.err != nil {
+		return 0, s.err
+	}
+	if age := now.Sub(s.written); age > s.maxAge {
+		return 0, fmt.Errorf("%w: written %v ago", ErrStale, age.Round(time.Millisecond))
+	}
+	return s.contents + now.Sub(s.written).Milliseconds(), nil
+}
+
+func readTimeFile(path string) (time.Time, int64, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return time.Time{}, 0, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return time.Time{}, 0, err
+	}
+	contents, err := strconv.ParseInt(strings.TrimSpace(string(data)), 10, 64)
+	if err != nil {
+		return time.Time{}, 0, fmt.Errorf("invalid time file %s: %w", path, err)
+	}
+	return info.ModTime(), contents, nil
+}
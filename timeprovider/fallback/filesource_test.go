@@ -0,0 +1,106 @@
+package fallback
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func writeTimeFile(t *testing.T, path string, contents int64, written time.Time) {
+	if err := os.WriteFile(path, []byte(strconv.FormatInt(contents, 10)+"\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chtimes(path, written, written); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestFileSource_Read(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "time")
+	written := time.UnixMilli(1_000_000)
+	// The daemon's clock is 30ms ahead of the system clock
+	writeTimeFile(t, path, written.UnixMilli()+30, written)
+	source := NewFileSource(path, time.Second)
+
+	timestamp, err := source.Read(written.Add(500 * time.Millisecond))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if timestamp != 1_000_530 {
+		t.Errorf("Expected 1000530, got %d", timestamp)
+	}
+}
+
+func TestFileSource_Stale(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "time")
+	written := time.UnixMilli(1_000_000)
+	writeTimeFile(t, path, written.UnixMilli(), written)
+	source := NewFileSource(path, time.Second)
+
+	if _, err := source.Read(written.Add(2 * time.Second)); !errors.Is(err, ErrStale) {
+		t.Errorf("Expected ErrStale, got %v", err)
+	}
+
+	// The daemon writes again
+	rewritten := written.Add(2 * time.Second)
+	writeTimeFile(t, path, rewritten.UnixMilli(), rewritten)
+	if _, err := source.Read(rewritten.Add(100 * time.Millisecond)); err != nil {
+		t.Errorf("Expected no error after the rewrite, got %v", err)
+	}
+}
+
+func TestFileSource_Invalid(t *testing.T) {
+	dir := t.TempDir()
+	now := time.Now()
+
+	if _, err := NewFileSource(filepath.Join(dir, "missing"), time.Second).Read(now); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Expected ErrNotExist, got %v", err)
+	}
+
+	path := filepath.Join(dir, "time")
+	os.WriteFile(path, []byte("soon"), 0600)
+	if _, err := NewFileSource(path, time.Second).Read(now); err == nil {
+		t.Error("Expected an error for invalid contents, got nil")
+	}
+}
+
+func TestFileSource_Refresh(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "time")
+	written := time.UnixMilli(1_000_000)
+	writeTimeFile(t, path, written.UnixMilli(), written)
+	source := NewFileSource(path, time.Second)
+	source.Read(written)
+
+	// Within the refresh interval the file is not read again
+	os.Remove(path)
+	if _, err := source.Read(written.Add(DefaultRefresh / 2)); err != nil {
+		t.Errorf("Expected the cached read, got %v", err)
+	}
+	if _, err := source.Read(written.Add(DefaultRefresh)); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Expected the file to be read again, got %v", err)
+	}
+}
+
+func TestNew_WithFileSource(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "time")
+	var logs []string
+	now := time.UnixMilli(1_000_000)
+	provider := newTestProvider(NewFileSource(path, time.Second), &now, &logs)
+
+	// Without the file the system clock is used
+	if timestamp := provider.GetTimeStamp(); timestamp != 1_000_000 {
+		t.Errorf("Expected 1000000, got %d", timestamp)
+	}
+
+	writeTimeFile(t, path, now.UnixMilli()+5, now)
+	now = now.Add(DefaultRefresh)
+	if timestamp := provider.GetTimeStamp(); timestamp != 1_000_015 {
+		t.Errorf("Expected 1000015, got %d", timestamp)
+	}
+	if status := provider.Status(); !status.Primary || status.Drift != 5*time.Millisecond {
+		t.Errorf("Expected the file with a drift of 5ms, got %+v", status)
+	}
+}
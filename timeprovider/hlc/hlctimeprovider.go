@@ -0,0 +1,143 @@
+package hlc
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// DefaultLogicalBits Leaves 39 bits of a 41 bit timestamp to the physical
+// clock, which lasts about 17 years from the offset
+const DefaultLogicalBits = 2
+
+// MaxLogicalBits is the largest logical component WithLogicalBits accepts
+const MaxLogicalBits = 20
+
+// DefaultMaxDrift is the largest lead of peer timestamps accepted by default
+const DefaultMaxDrift = time.Second
+
+// DefaultTimeStampBits is the size of the timestamp field of the default ID
+// layout
+const DefaultTimeStampBits = 41
+
+var ErrTooFarAhead = errors.New("peer timestamp too far ahead of the physical clock")
+var ErrOutOfRange = errors.New("peer timestamp out of range")
+
+// TimeProvider Implements the TimeProvider interface with a hybrid logical
+// clock. Timestamps hold the physical milliseconds since the offset in the
+// high bits and a logical component in the low ones. While the physical clock
+// stalls the timestamp holds still and the worker counter separates the IDs;
+// while it is behind the last timestamp, e.g. after being stepped back, every
+// read advances the logical component, so workers that exhausted their
+// counter get a new timestamp without waiting for the clock to catch up.
+// Observing the timestamp of a peer advances the logical
+// component past it, carrying into the physical part when it overflows, which
+// keeps causally related IDs ordered across nodes.
+type timeProvider struct {
+	epochOffset  int64
+	logicalBits  int64
+	maxDrift     time.Duration
+	maxTimeStamp int64
+	physical     func() int64 // Milliseconds since the epoch
+	mutex        sync.Mutex
+	last         int64
+}
+
+// Option Configures the hybrid logical clock TimeProvider
+type Option func(*timeProvider)
+
+// WithLogicalBits Sets the size of the logical component, clamped to
+// 0-MaxLogicalBits. Every bit halves how long the physical part lasts.
+func WithLogicalBits(bits int64) Option {
+	return func(t *timeProvider) {
+		t.logicalBits = min(max(bits, 0), MaxLogicalBits)
+	}
+}
+
+// WithMaxDrift Rejects peer timestamps whose physical part is more than
+// maxDrift ahead of the local clock, DefaultMaxDrift by default, so a peer
+// with a broken clock cannot drag this node into the future. Peers are
+// trusted when it is 0.
+func WithMaxDrift(maxDrift time.Duration) Option {
+	return func(t *timeProvider) {
+		t.maxDrift = maxDrift
+	}
+}
+
+// WithTimeStampBits Sets the size of the timestamp field of the ID layout,
+// DefaultTimeStampBits by default. Peer timestamps that do not fit are
+// rejected whatever the drift.
+func WithTimeStampBits(bits int64) Option {
+	return func(t *timeProvider) {
+		t.maxTimeStamp = 1<<min(max(bits, 1), 62) - 1
+	}
+}
+
+// New TimeProvider based on provided epoch, in milliseconds
+func New(epochOffset int64, opts ...Option) *timeProvider {
+	t := &timeProvider{
+		epochOffset:  epochOffset,
+		logicalBits:  DefaultLogicalBits,
+		maxDrift:     DefaultMaxDrift,
+		maxTimeStamp: 1<<DefaultTimeStampBits - 1,
+		physical:     func() int64 { return time.Now().UTC().UnixMilli() },
+	}
+	for _, opt := range opts {
+		opt(t)
+	}
+	return t
+}
+
+// GetTimeStamp Returns a timestamp no smaller than every timestamp returned
+// before and greater than every timestamp observed. It only repeats the last
+// one while the physical clock stands still at it.
+func (t *timeProvider) GetTimeStamp() int64 {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if now := t.now(); now < t.last {
+		t.last++
+	} else {
+		t.last = now
+	}
+	return t.last
+}
+
+// Observe Merges a timestamp received from a peer, so every later timestamp
+// is greater than it. Timestamps outside the timestamp field of the layout
+// are rejected with ErrOutOfRange.
+func (t *timeProvider) Observe(timestamp int64) error {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if timestamp < 0 || timestamp >= t.maxTimeStamp {
+		return ErrOutOfRange
+	}
+	if t.maxDrift > 0 {
+		ahead := (timestamp - t.now()) >> t.logicalBits
+		if time.Duration(ahead)*time.Millisecond > t.maxDrift {
+			return ErrTooFarAhead
+		}
+	}
+	t.last = max(t.last, timestamp+1)
+	return nil
+}
+
+// Last Returns the latest timestamp returned or observed, for passing on to
+// peers
+func (t *timeProvider) Last() int64 {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	return t.last
+}
+
+// Decode Splits a timestamp into its physical time and logical component
+func (t *timeProvider) Decode(timestamp int64) (time.Time, int64) {
+	physical := time.UnixMilli(t.epochOffset + timestamp>>t.logicalBits).UTC()
+	return physical, timestamp & (1<<t.logicalBits - 1)
+}
+
+// now Returns the physical clock with an empty logical component
+func (t *timeProvider) now() int64 {
+	return (t.physical() - t.epochOffset) << t.logicalBits
+}
@@ -0,0 +1,253 @@
+package hlc
+
+import (
+	"math"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+	"uidGenerator/generator"
+)
+
+func newTestProvider(physical *int64, opts ...Option) *timeProvider {
+	provider := New(0, opts...)
+	provider.physical = func() int64 { return *physical }
+	return provider
+}
+
+func TestNew(t *testing.T) {
+	offset := int64(1420070400000)
+	provider := New(offset)
+
+	if provider.epochOffset != offset {
+		t.Errorf("Expected offset %d, got %d", offset, provider.epochOffset)
+	}
+	if provider.logicalBits != DefaultLogicalBits {
+		t.Errorf("Expected %d logical bits, got %d", DefaultLogicalBits, provider.logicalBits)
+	}
+}
+
+func TestWithLogicalBits(t *testing.T) {
+	testCases := []struct {
+		bits     int64
+		expected int64
+	}{
+		{0, 0},
+		{8, 8},
+		{-1, 0},
+		{40, MaxLogicalBits},
+	}
+
+	for _, tc := range testCases {
+		provider := New(0, WithLogicalBits(tc.bits))
+		if provider.logicalBits != tc.expected {
+			t.Errorf("Expected %d logical bits for %d, got %d", tc.expected, tc.bits, provider.logicalBits)
+		}
+	}
+}
+
+func TestGetTimeStamp_PhysicalClock(t *testing.T) {
+	physical := int64(1000)
+	provider := newTestProvider(&physical, WithLogicalBits(4))
+
+	if timestamp := provider.GetTimeStamp(); timestamp != 1000<<4 {
+		t.Errorf("Expected %d, got %d", 1000<<4, timestamp)
+	}
+
+	physical = 1005
+	if timestamp := provider.GetTimeStamp(); timestamp != 1005<<4 {
+		t.Errorf("Expected %d, got %d", 1005<<4, timestamp)
+	}
+}
+
+func TestGetTimeStamp_StalledAndBackwardsClock(t *testing.T) {
+	physical := int64(1000)
+	provider := newTestProvider(&physical, WithLogicalBits(2))
+
+	// The timestamp holds still while the clock stalls, the worker counter
+	// separates the IDs, and advances the logical part while it is behind
+	testCases := []struct {
+		physical int64
+		expected int64
+	}{
+		{1000, 1000 << 2},
+		{1000, 1000 << 2},
+		{990, 1000<<2 | 1},
+		{995, 1000<<2 | 2},
+		{1000, 1000<<2 | 3},
+		{1000, 1001 << 2},
+	}
+	for i, tc := range testCases {
+		physical = tc.physical
+		if timestamp := provider.GetTimeStamp(); timestamp != tc.expected {
+			t.Errorf("Expected %d at %d, got %d", tc.expected, i, timestamp)
+		}
+	}
+
+	// The clock catches up again
+	physical = 1010
+	if timestamp := provider.GetTimeStamp(); timestamp != 1010<<2 {
+		t.Errorf("Expected %d, got %d", 1010<<2, timestamp)
+	}
+}
+
+func TestObserve_OrdersAfterPeer(t *testing.T) {
+	physical := int64(1000)
+	provider := newTestProvider(&physical, WithLogicalBits(4))
+
+	// A peer whose clock is 50ms ahead
+	peer := int64(1050<<4 | 3)
+	if err := provider.Observe(peer); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+	if last := provider.Last(); last != peer+1 {
+		t.Errorf("Expected %d, got %d", peer+1, last)
+	}
+
+	// Older peer timestamps do not move the clock
+	if err := provider.Observe(900 << 4); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+	if last := provider.Last(); last != peer+1 {
+		t.Errorf("Expected %d, got %d", peer+1, last)
+	}
+
+	// The physical clock is behind the peer, so reads advance the logical part
+	if timestamp := provider.GetTimeStamp(); timestamp != peer+2 {
+		t.Errorf("Expected %d, got %d", peer+2, timestamp)
+	}
+}
+
+func TestObserve_CarriesIntoPhysical(t *testing.T) {
+	physical := int64(1000)
+	provider := newTestProvider(&physical, WithLogicalBits(2))
+
+	if err := provider.Observe(1000<<2 | 3); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+	if last := provider.Last(); last != 1001<<2 {
+		t.Errorf("Expected %d, got %d", 1001<<2, last)
+	}
+}
+
+func TestObserve_DefaultMaxDrift(t *testing.T) {
+	physical := int64(1000)
+	provider := newTestProvider(&physical, WithLogicalBits(4))
+
+	if err := provider.Observe(2000 << 4); err != nil {
+		t.Errorf("Expected no error within %v, got %v", DefaultMaxDrift, err)
+	}
+	if err := provider.Observe(2001 << 4); err != ErrTooFarAhead {
+		t.Errorf("Expected ErrTooFarAhead, got %v", err)
+	}
+}
+
+func TestObserve_OutOfRange(t *testing.T) {
+	physical := int64(1000)
+	provider := newTestProvider(&physical, WithMaxDrift(0), WithTimeStampBits(41))
+
+	for _, timestamp := range []int64{-1, 1<<41 - 1, 1 << 41, math.MaxInt64} {
+		if err := provider.Observe(timestamp); err != ErrOutOfRange {
+			t.Errorf("Expected ErrOutOfRange for %d, got %v", timestamp, err)
+		}
+	}
+	if last := provider.Last(); last != 0 {
+		t.Errorf("Expected rejected timestamps to be ignored, got %d", last)
+	}
+	if err := provider.Observe(1<<41 - 2); err != nil {
+		t.Errorf("Expected the largest timestamp to be accepted, got %v", err)
+	}
+}
+
+func TestObserve_MaxDrift(t *testing.T) {
+	physical := int64(1000)
+	provider := newTestProvider(&physical, WithLogicalBits(4), WithMaxDrift(100*time.Millisecond))
+
+	if err := provider.Observe(1100 << 4); err != nil {
+		t.Errorf("Expected no error within the drift, got %v", err)
+	}
+	if err := provider.Observe(1101 << 4); err != ErrTooFarAhead {
+		t.Errorf("Expected ErrTooFarAhead, got %v", err)
+	}
+	if last := provider.Last(); last != 1100<<4+1 {
+		t.Errorf("Expected the rejected timestamp to be ignored, got %d", last)
+	}
+}
+
+func TestDecode(t *testing.T) {
+	offset := int64(1420070400000)
+	provider := New(offset, WithLogicalBits(4))
+
+	expected := time.Date(2024, 5, 6, 7, 8, 9, 0, time.UTC)
+	timestamp := (expected.UnixMilli()-offset)<<4 | 5
+	physical, logical := provider.Decode(timestamp)
+	if !physical.Equal(expected) {
+		t.Errorf("Expected %v, got %v", expected, physical)
+	}
+	if logical != 5 {
+		t.Errorf("Expected logical 5, got %d", logical)
+	}
+}
+
+func TestGetTimeStamp_TightLoopFollowsWallClock(t *testing.T) {
+	provider := New(1420070400000)
+
+	var timestamp int64
+	for i := 0; i < 2000000; i++ {
+		timestamp = provider.GetTimeStamp()
+	}
+
+	physical, _ := provider.Decode(timestamp)
+	if lead := physical.Sub(time.Now()); lead > 10*time.Millisecond {
+		t.Errorf("Expected the physical part to follow the wall clock, got %v ahead", lead)
+	}
+}
+
+func TestGetTimeStamp_SteppedBackClockDoesNotBlockWorkers(t *testing.T) {
+	var physical atomic.Int64
+	physical.Store(time.Now().UnixMilli())
+	provider := New(1420070400000)
+	provider.physical = physical.Load
+	worker := &generator.WorkerVariant{WorkerID: 1, ThreadId: 1, TimeProvider: provider}
+	if _, err := worker.GenerateID(1); err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	// Stepped back 5s and frozen there, so only the logical part can advance
+	physical.Add(-5000)
+	done := make(chan error, 1)
+	go func() {
+		_, err := worker.GenerateID(2000)
+		done <- err
+	}()
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("Expected no error, got %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Expected the counter to roll over into a logical tick")
+	}
+}
+
+func TestGetTimeStamp_Concurrent(t *testing.T) {
+	provider := New(1420070400000)
+	const goroutines, calls = 8, 1000
+
+	var wg sync.WaitGroup
+	for i := 0; i < goroutines; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var previous int64
+			for j := 0; j < calls; j++ {
+				timestamp := provider.GetTimeStamp()
+				if timestamp < previous {
+					t.Errorf("Expected timestamps not to decrease, got %d after %d", timestamp, previous)
+				}
+				previous = timestamp
+			}
+		}()
+	}
+	wg.Wait()
+}
@@ -1,29 +1,182 @@
 package julian
 
 import (
+	"errors"
 	"fmt"
 	"strconv"
 	"time"
+	"uidGenerator/timeprovider"
 )
 
+// MaxPrecision is the largest number of sub-second digits, i.e. milliseconds
+const MaxPrecision = 3
+
+// DefaultTimeStampBits is the size of the timestamp field of the default ID
+// layout
+const DefaultTimeStampBits = 41
+
 // TimeProvider Implement the TimeProvider interface using Julian calendar
 type timeProvider struct {
-	offset int64
+	offset        int64
+	precision     int
+	fullYear      bool
+	location      *time.Location
+	timeStampBits int64
+}
+
+var ErrInvalidTimeStamp = errors.New("invalid julian time stamp")
+var ErrOutOfRange = errors.New("julian time stamp does not fit the timestamp bits")
+
+// Option Configures the Julian TimeProvider
+type Option func(*timeProvider)
+
+// WithPrecision Appends the given number of sub-second digits to the
+// timestamp, e.g. 3 for YYDDDSSSSSmmm. Values are clamped to 0-MaxPrecision.
+func WithPrecision(digits int) Option {
+	return func(t *timeProvider) {
+		t.precision = min(max(digits, 0), MaxPrecision)
+	}
+}
+
+// WithFullYear Encodes all four digits of the year, YYYYDDDSSSSS, so the
+// timestamps keep increasing past 2099 when the two digit year wraps to 00
+func WithFullYear() Option {
+	return func(t *timeProvider) {
+		t.fullYear = true
+	}
+}
+
+// WithLocation Encodes the wall clock of loc instead of UTC. When the clock
+// is set back, e.g. at the end of daylight saving time, the repeated interval
+// is smeared so timestamps never go backwards; see wallClock.
+func WithLocation(loc *time.Location) Option {
+	return func(t *timeProvider) {
+		t.location = loc
+	}
+}
+
+// WithTimeStampBits Sets the size of the timestamp field of the ID layout
+// the timestamps have to fit, DefaultTimeStampBits by default
+func WithTimeStampBits(bits int64) Option {
+	return func(t *timeProvider) {
+		t.timeStampBits = bits
+	}
 }
 
 // New TimeProvider
-func New(offset int64) *timeProvider {
-	return &timeProvider{
-		offset: offset,
+func New(offset int64, opts ...Option) *timeProvider {
+	t := &timeProvider{
+		offset:        offset,
+		timeStampBits: DefaultTimeStampBits,
 	}
+	for _, opt := range opts {
+		opt(t)
+	}
+	return t
+}
+
+// Validate Returns ErrOutOfRange when the current timestamp, after the
+// offset, does not fit the timestamp bits, e.g. the full year with
+// milliseconds and no offset
+func (t *timeProvider) Validate() error {
+	timestamp, err := t.timeStamp()
+	if err != nil {
+		return err
+	}
+	if timestamp < 0 || timestamp >= 1<<min(max(t.timeStampBits, 1), 62) {
+		return fmt.Errorf("%w: %d", ErrOutOfRange, timestamp)
+	}
+	return nil
 }
 
-// GetTimeStamp Returns the current time in milliseconds
+// GetTimeStamp Returns the current time as YYDDDSSSSS (or YYYYDDDSSSSS)
+// followed by the configured sub-second digits
 func (t *timeProvider) GetTimeStamp() int64 {
-	return convertTimeToJulianCalendarIncludingTime(time.Now().UTC(), t.offset)
+	timestamp, _ := t.timeStamp()
+	return timestamp
+}
+
+// Checked Returns the provider as a FallibleTimeProvider, which reports
+// errors building the timestamp instead of discarding them
+func (t *timeProvider) Checked() timeprovider.FallibleTimeProvider {
+	return (*checkedTimeProvider)(t)
+}
+
+type checkedTimeProvider timeProvider
+
+func (c *checkedTimeProvider) GetTimeStamp() (int64, error) {
+	return (*timeProvider)(c).timeStamp()
+}
+
+func (t *timeProvider) timeStamp() (int64, error) {
+	now := time.Now().UTC()
+	if t.location != nil {
+		now = wallClock(time.Now(), t.location)
+	}
+	if t.precision == 0 && !t.fullYear {
+		return julianTimeStamp(now, t.offset)
+	}
+	return encode(now, t.precision, t.fullYear, t.offset), nil
+}
+
+// Decode Returns the time a timestamp of this provider was taken at. Two
+// digit years are read as 2000-2099. Wall clock times inside a smeared
+// interval are read as they are and may be off by up to the clock shift.
+func (t *timeProvider) Decode(timestamp int64) (time.Time, error) {
+	wall, err := decode(timestamp, t.precision, t.fullYear, t.offset)
+	if err != nil || t.location == nil {
+		return wall, err
+	}
+	return time.Date(wall.Year(), wall.Month(), wall.Day(), wall.Hour(), wall.Minute(), wall.Second(), wall.Nanosecond(), t.location), nil
+}
+
+// wallClock Returns the wall clock of loc at now as a UTC time with the same
+// fields. Around a transition that sets the clock back by shift, the wall
+// clock instead runs at half speed from shift before to shift after the
+// transition, so it keeps increasing and meets the real wall clock again at
+// both ends.
+func wallClock(now time.Time, loc *time.Location) time.Time {
+	local := now.In(loc)
+	_, offset := local.Zone()
+	start, end := local.ZoneBounds()
+
+	// Transition ahead of now
+	if !end.IsZero() {
+		_, after := end.In(loc).Zone()
+		shift := time.Duration(offset-after) * time.Second
+		if shift > 0 && !now.Before(end.Add(-shift)) {
+			return smear(now, end, offset, shift)
+		}
+	}
+
+	// Transition behind now
+	if !start.IsZero() {
+		_, before := start.Add(-time.Nanosecond).In(loc).Zone()
+		shift := time.Duration(before-offset) * time.Second
+		if shift > 0 && now.Before(start.Add(shift)) {
+			return smear(now, start, before, shift)
+		}
+	}
+
+	return now.UTC().Add(time.Duration(offset) * time.Second)
+}
+
+// smear Maps now, within shift of a transition, onto the wall clock running
+// at half speed from the offset in effect before the transition
+func smear(now time.Time, transition time.Time, offsetBefore int, shift time.Duration) time.Time {
+	windowStart := transition.Add(-shift)
+	elapsed := now.Sub(windowStart)
+	return windowStart.UTC().Add(time.Duration(offsetBefore)*time.Second + elapsed/2)
 }
 
 func convertTimeToJulianCalendarIncludingTime(time time.Time, offset int64) int64 {
+	julianTime, _ := julianTimeStamp(time, offset)
+	return julianTime
+}
+
+// julianTimeStamp Builds YYDDDSSSSS, failing where it does not fit an int,
+// such as on 32 bit platforms
+func julianTimeStamp(time time.Time, offset int64) (int64, error) {
 	// From time get last 2 digits of the year
 	last2DigitsOfTheYear := time.Year() % 100
 
@@ -34,7 +187,70 @@ func convertTimeToJulianCalendarIncludingTime(time time.Time, offset int64) int6
 	secondsSinceBeginningOfTheDay := time.Hour()*3600 + time.Minute()*60 + time.Second()
 
 	// Concatenate all the above into a string and convert to a number
-	julianTime, _ := strconv.Atoi(fmt.Sprintf("%02d%03d%05d", last2DigitsOfTheYear, daysSinceBeginningOfTheYear, secondsSinceBeginningOfTheDay))
+	julianTime, err := strconv.Atoi(fmt.Sprintf("%02d%03d%05d", last2DigitsOfTheYear, daysSinceBeginningOfTheYear, secondsSinceBeginningOfTheDay))
+	if err != nil {
+		return 0, err
+	}
+
+	return int64(julianTime) - offset, nil
+}
 
-	return int64(julianTime) - offset
+// convertTimeToJulianCalendarWithPrecision Appends digits sub-second digits to
+// the YYDDDSSSSS encoding, so it stays readable as YYDDDSSSSSmmm
+func convertTimeToJulianCalendarWithPrecision(time time.Time, digits int, offset int64) int64 {
+	return encode(time, digits, false, offset)
+}
+
+// encode Builds YYDDDSSSSS, or YYYYDDDSSSSS with fullYear, followed by digits
+// sub-second digits
+func encode(time time.Time, digits int, fullYear bool, offset int64) int64 {
+	year := int64(time.Year() % 100)
+	if fullYear {
+		year = int64(time.Year())
+	}
+	secondsSinceBeginningOfTheDay := int64(time.Hour()*3600 + time.Minute()*60 + time.Second())
+	seconds := year*100000000 + int64(time.YearDay())*100000 + secondsSinceBeginningOfTheDay
+
+	scale := pow10(digits)
+	fraction := int64(time.Nanosecond()) / (1e9 / scale)
+	return seconds*scale + fraction - offset
+}
+
+// decode Reverses encode
+func decode(timestamp int64, digits int, fullYear bool, offset int64) (time.Time, error) {
+	value := timestamp + offset
+	if value < 0 {
+		return time.Time{}, ErrInvalidTimeStamp
+	}
+
+	scale := pow10(digits)
+	fraction := value % scale
+	seconds := value / scale
+	secondsOfTheDay := seconds % 100000
+	dayOfTheYear := int((seconds / 100000) % 1000)
+	year := int(seconds / 100000000)
+	if !fullYear {
+		if year > 99 {
+			return time.Time{}, ErrInvalidTimeStamp
+		}
+		year += 2000
+	}
+
+	beginningOfTheYear := time.Date(year, 1, 1, 0, 0, 0, 0, time.UTC)
+	daysInTheYear := beginningOfTheYear.AddDate(1, 0, -1).YearDay()
+	if dayOfTheYear < 1 || dayOfTheYear > daysInTheYear || secondsOfTheDay >= 86400 {
+		return time.Time{}, ErrInvalidTimeStamp
+	}
+
+	return beginningOfTheYear.
+		AddDate(0, 0, dayOfTheYear-1).
+		Add(time.Duration(secondsOfTheDay)*time.Second + time.Duration(fraction)*(time.Second/time.Duration(scale))), nil
+}
+
+func pow10(digits int) int64 {
+	scale := int64(1)
+	for i := 0; i < digits; i++ {
+		scale *= 10
+	}
+	return scale
 }
@@ -1,10 +1,12 @@
 package julian
 
 import (
+	"errors"
 	"fmt"
 	"strconv"
 	"testing"
 	"time"
+	"uidGenerator/timeprovider"
 )
 
 func TestConvertTimeToJulianCalendarIncludingTime(t *testing.T) {
@@ -288,3 +290,307 @@ func TestConvertTimeToJulianCalendar_TimeComponents(t *testing.T) {
 		})
 	}
 }
+
+func TestConvertTimeToJulianCalendarWithPrecision(t *testing.T) {
+	testedTime := time.Date(2020, 10, 12, 13, 14, 15, 678912345, time.UTC)
+
+	testCases := []struct {
+		digits   int
+		expected int64
+	}{
+		{1, 20286476556},
+		{2, 202864765567},
+		{3, 2028647655678},
+	}
+
+	for _, tc := range testCases {
+		t.Run(strconv.Itoa(tc.digits), func(t *testing.T) {
+			actual := convertTimeToJulianCalendarWithPrecision(testedTime, tc.digits, 0)
+			if actual != tc.expected {
+				t.Errorf("Expected %d, got %d", tc.expected, actual)
+			}
+		})
+	}
+}
+
+func TestConvertTimeToJulianCalendarWithPrecision_Offset(t *testing.T) {
+	testedTime := time.Date(2022, 1, 1, 0, 0, 0, 1000000, time.UTC)
+	offset := int64(2000100000000)
+	expected := int64(2200100000001) - offset
+	actual := convertTimeToJulianCalendarWithPrecision(testedTime, 3, offset)
+	if actual != expected {
+		t.Errorf("Expected %d, got %d", expected, actual)
+	}
+}
+
+func TestConvertTimeToJulianCalendarWithPrecision_Ordering(t *testing.T) {
+	// Milliseconds order within a second and across second boundaries
+	times := []time.Time{
+		time.Date(2021, 12, 31, 23, 59, 58, 999000000, time.UTC),
+		time.Date(2021, 12, 31, 23, 59, 59, 0, time.UTC),
+		time.Date(2021, 12, 31, 23, 59, 59, 1000000, time.UTC),
+		time.Date(2021, 12, 31, 23, 59, 59, 999000000, time.UTC),
+		time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+
+	var previous int64
+	for i, testedTime := range times {
+		actual := convertTimeToJulianCalendarWithPrecision(testedTime, 3, 0)
+		if i > 0 && actual <= previous {
+			t.Errorf("Expected %d to be greater than %d", actual, previous)
+		}
+		previous = actual
+	}
+}
+
+func TestNew_WithPrecision(t *testing.T) {
+	testCases := []struct {
+		digits   int
+		expected int
+	}{
+		{0, 0},
+		{3, 3},
+		{-1, 0},
+		{9, MaxPrecision},
+	}
+
+	for _, tc := range testCases {
+		provider := New(0, WithPrecision(tc.digits), WithTimeStampBits(62))
+		if provider.precision != tc.expected {
+			t.Errorf("Expected precision %d for %d, got %d", tc.expected, tc.digits, provider.precision)
+		}
+	}
+}
+
+func TestGetTimeStamp_Milliseconds(t *testing.T) {
+	seconds := New(0)
+	milliseconds := New(0, WithPrecision(3), WithTimeStampBits(62))
+
+	before := seconds.GetTimeStamp()
+	timestamp := milliseconds.GetTimeStamp()
+	after := seconds.GetTimeStamp()
+
+	if timestamp/1000 < before || timestamp/1000 > after {
+		t.Errorf("Expected %d to extend a timestamp between %d and %d", timestamp, before, after)
+	}
+}
+
+func TestEncode_FullYearAcrossCenturies(t *testing.T) {
+	times := []time.Time{
+		time.Date(2099, 12, 31, 23, 59, 59, 0, time.UTC),
+		time.Date(2100, 1, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2100, 2, 28, 23, 59, 59, 0, time.UTC),
+		time.Date(2100, 3, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2100, 12, 31, 12, 0, 0, 0, time.UTC),
+		time.Date(2101, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+	expected := []int64{
+		209936586399,
+		210000100000,
+		210005986399,
+		210006000000,
+		210036543200,
+		210100100000,
+	}
+
+	for i, testedTime := range times {
+		actual := encode(testedTime, 0, true, 0)
+		if actual != expected[i] {
+			t.Errorf("Expected %d for %v, got %d", expected[i], testedTime, actual)
+		}
+		if i > 0 && actual <= expected[i-1] {
+			t.Errorf("Expected %d to be greater than %d", actual, expected[i-1])
+		}
+	}
+}
+
+func TestEncode_TwoDigitYearWraps(t *testing.T) {
+	before := encode(time.Date(2099, 12, 31, 23, 59, 59, 0, time.UTC), 0, false, 0)
+	after := encode(time.Date(2100, 1, 1, 0, 0, 0, 0, time.UTC), 0, false, 0)
+	if after >= before {
+		t.Errorf("Expected %d to wrap below %d", after, before)
+	}
+}
+
+func TestDecode_RoundTrip(t *testing.T) {
+	testCases := []struct {
+		time     time.Time
+		digits   int
+		fullYear bool
+		offset   int64
+	}{
+		{time.Date(2024, 2, 29, 12, 30, 45, 0, time.UTC), 0, false, 0},
+		{time.Date(2024, 12, 31, 23, 59, 59, 123000000, time.UTC), 3, false, 2000100000000},
+		{time.Date(2099, 12, 31, 23, 59, 59, 0, time.UTC), 0, true, 201500100000},
+		{time.Date(2100, 3, 1, 0, 0, 0, 500000000, time.UTC), 1, true, 0},
+		{time.Date(2400, 12, 31, 6, 0, 0, 0, time.UTC), 2, true, 0},
+	}
+
+	for _, tc := range testCases {
+		opts := []Option{WithPrecision(tc.digits), WithTimeStampBits(62)}
+		if tc.fullYear {
+			opts = append(opts, WithFullYear())
+		}
+		provider := New(tc.offset, opts...)
+
+		decoded, err := provider.Decode(encode(tc.time, tc.digits, tc.fullYear, tc.offset))
+		if err != nil {
+			t.Errorf("Expected no error for %v, got %v", tc.time, err)
+			continue
+		}
+		if !decoded.Equal(tc.time) {
+			t.Errorf("Expected %v, got %v", tc.time, decoded)
+		}
+	}
+}
+
+func TestDecode_Invalid(t *testing.T) {
+	testCases := []struct {
+		timestamp int64
+		fullYear  bool
+	}{
+		{210036600000, true}, // Day 366 of 2100, which is not a leap year
+		{210000000000, true}, // Day 0
+		{2400186400, false},  // 86400 seconds
+		{-1, false},
+		{210000100000, false}, // Three digit year without WithFullYear
+	}
+
+	for _, tc := range testCases {
+		_, err := decode(tc.timestamp, 0, tc.fullYear, 0)
+		if err != ErrInvalidTimeStamp {
+			t.Errorf("Expected ErrInvalidTimeStamp for %d, got %v", tc.timestamp, err)
+		}
+	}
+
+	if _, err := decode(240036600000, 0, true, 0); err != nil {
+		t.Errorf("Expected day 366 of the leap year 2400 to be valid, got %v", err)
+	}
+}
+
+func TestGetTimeStamp_FullYear(t *testing.T) {
+	provider := New(0, WithFullYear())
+	timestamp := provider.GetTimeStamp()
+
+	decoded, err := provider.Decode(timestamp)
+	if err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+	if time.Since(decoded) > time.Minute || time.Until(decoded) > time.Second {
+		t.Errorf("Expected %v to be close to now", decoded)
+	}
+}
+
+func loadLocation(t *testing.T, name string) *time.Location {
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		t.Skipf("Time zone %s not available: %v", name, err)
+	}
+	return loc
+}
+
+func TestWallClock_FallBackNeverGoesBackwards(t *testing.T) {
+	warsaw := loadLocation(t, "Europe/Warsaw")
+
+	// 03:00 CEST becomes 02:00 CET at 01:00 UTC
+	transition := time.Date(2024, 10, 27, 1, 0, 0, 0, time.UTC)
+	var previous int64
+	for now := transition.Add(-3 * time.Hour); now.Before(transition.Add(3 * time.Hour)); now = now.Add(time.Second) {
+		actual := encode(wallClock(now, warsaw), 3, false, 0)
+		if actual < previous {
+			t.Fatalf("Expected %d at %v to not be less than %d", actual, now, previous)
+		}
+		previous = actual
+	}
+}
+
+func TestWallClock_MatchesLocalTimeOutsideTheSmear(t *testing.T) {
+	warsaw := loadLocation(t, "Europe/Warsaw")
+
+	testCases := []struct {
+		now      time.Time
+		expected time.Time
+	}{
+		// An hour before the smear starts
+		{time.Date(2024, 10, 26, 23, 0, 0, 0, time.UTC), time.Date(2024, 10, 27, 1, 0, 0, 0, time.UTC)},
+		// Start of the smear, 02:00 CEST
+		{time.Date(2024, 10, 27, 0, 0, 0, 0, time.UTC), time.Date(2024, 10, 27, 2, 0, 0, 0, time.UTC)},
+		// Transition, half way through the smear
+		{time.Date(2024, 10, 27, 1, 0, 0, 0, time.UTC), time.Date(2024, 10, 27, 2, 30, 0, 0, time.UTC)},
+		// End of the smear, 03:00 CET
+		{time.Date(2024, 10, 27, 2, 0, 0, 0, time.UTC), time.Date(2024, 10, 27, 3, 0, 0, 0, time.UTC)},
+		// Spring forward is not smeared, 01:59:59 CET is followed by 03:00 CEST
+		{time.Date(2024, 3, 31, 0, 59, 59, 0, time.UTC), time.Date(2024, 3, 31, 1, 59, 59, 0, time.UTC)},
+		{time.Date(2024, 3, 31, 1, 0, 0, 0, time.UTC), time.Date(2024, 3, 31, 3, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tc := range testCases {
+		actual := wallClock(tc.now, warsaw)
+		if !actual.Equal(tc.expected) {
+			t.Errorf("Expected %v at %v, got %v", tc.expected, tc.now, actual)
+		}
+	}
+}
+
+func TestWallClock_UTC(t *testing.T) {
+	now := time.Date(2024, 10, 27, 1, 0, 0, 0, time.UTC)
+	if actual := wallClock(now, time.UTC); !actual.Equal(now) {
+		t.Errorf("Expected %v, got %v", now, actual)
+	}
+}
+
+func TestValidate_OutOfRange(t *testing.T) {
+	testCases := []struct {
+		offset int64
+		opts   []Option
+	}{
+		// About 2.0e14 shifted by 22 bits overflows an ID
+		{0, []Option{WithFullYear(), WithPrecision(3)}},
+		{0, []Option{WithPrecision(3)}},
+		// Negative timestamps
+		{9912312345, nil},
+	}
+
+	for _, tc := range testCases {
+		if err := New(tc.offset, tc.opts...).Validate(); !errors.Is(err, ErrOutOfRange) {
+			t.Errorf("Expected ErrOutOfRange for offset %d, got %v", tc.offset, err)
+		}
+	}
+
+	// An offset in the same format brings them back in range
+	if err := New(202400100000000, WithFullYear(), WithPrecision(3)).Validate(); err != nil {
+		t.Errorf("Expected no error with an offset, got %v", err)
+	}
+}
+
+func TestGetTimeStamp_WithLocation(t *testing.T) {
+	tokyo := loadLocation(t, "Asia/Tokyo")
+	provider := New(0, WithFullYear(), WithLocation(tokyo))
+
+	decoded, err := provider.Decode(provider.GetTimeStamp())
+	if err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+	if decoded.Location() != tokyo {
+		t.Errorf("Expected location %v, got %v", tokyo, decoded.Location())
+	}
+	if time.Since(decoded) > time.Minute || time.Until(decoded) > time.Second {
+		t.Errorf("Expected %v to be close to now", decoded)
+	}
+}
+
+func TestChecked(t *testing.T) {
+	provider := New(2000100000)
+	checked := timeprovider.Adapt(provider)
+
+	before := provider.GetTimeStamp()
+	timestamp, err := checked.GetTimeStamp()
+	after := provider.GetTimeStamp()
+
+	if err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+	if timestamp < before || timestamp > after {
+		t.Errorf("Expected %d to be between %d and %d", timestamp, before, after)
+	}
+}
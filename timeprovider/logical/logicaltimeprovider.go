@@ -0,0 +1,206 @@
+package logical
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"uidGenerator/timeprovider"
+)
+
+// DefaultReserve is how many timestamps are reserved with every write
+const DefaultReserve = 1000
+
+// DefaultTimeStampBits is the size of the timestamp field of the default ID
+// layout
+const DefaultTimeStampBits = 41
+
+var ErrInvalidState = errors.New("invalid logical clock state file")
+var ErrOutOfRange = errors.New("observed timestamp out of range")
+
+// TimeProvider Implements the TimeProvider interface with a Lamport counter
+// instead of wall time, for air-gapped nodes and deterministic tests. Every
+// call returns the next value of the counter. The counter is persisted in
+// blocks: before handing out a timestamp beyond the persisted one, the end of
+// the next block is written to the state file, and after a restart the
+// counter continues from there, so it never goes backwards.
+type timeProvider struct {
+	path         string
+	reserve      int64
+	maxTimeStamp int64
+
+	mutex    sync.Mutex
+	counter  int64 // Last returned or observed timestamp
+	reserved int64 // Largest timestamp covered by the state file
+	err      error // Error of the last failed write
+}
+
+// Option Configures the logical TimeProvider
+type Option func(*timeProvider)
+
+// WithReserve Sets how many timestamps are reserved with every write. Larger
+// blocks mean fewer writes but bigger gaps after a restart.
+func WithReserve(reserve int64) Option {
+	return func(t *timeProvider) {
+		t.reserve = max(reserve, 1)
+	}
+}
+
+// WithTimeStampBits Sets the size of the timestamp field of the ID layout,
+// DefaultTimeStampBits by default. Observed timestamps that do not fit are
+// rejected and reserved blocks end at the largest one that does.
+func WithTimeStampBits(bits int64) Option {
+	return func(t *timeProvider) {
+		t.maxTimeStamp = 1<<min(max(bits, 1), 62) - 1
+	}
+}
+
+// New TimeProvider continuing from the state file at path, which is created
+// when missing. The counter is kept in memory only when path is empty.
+func New(path string, opts ...Option) (*timeProvider, error) {
+	t := &timeProvider{
+		path:         path,
+		reserve:      DefaultReserve,
+		maxTimeStamp: 1<<DefaultTimeStampBits - 1,
+	}
+	for _, opt := range opts {
+		opt(t)
+	}
+	if path == "" {
+		return t, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+	if err == nil {
+		counter, err := strconv.ParseInt(strings.TrimSpace(string(data)), 10, 64)
+		if err != nil || counter < 0 {
+			return nil, fmt.Errorf("%w %s", ErrInvalidState, path)
+		}
+		t.counter = counter
+		t.reserved = counter
+	}
+	return t, nil
+}
+
+// GetTimeStamp Returns the next value of the counter. When the next block
+// cannot be persisted the counter stops advancing, so generation waits, and
+// the error is reported by Err.
+func (t *timeProvider) GetTimeStamp() int64 {
+	timestamp, _ := t.next()
+	return timestamp
+}
+
+// Checked Returns the provider as a FallibleTimeProvider, which returns the
+// error when the next block cannot be persisted, so generation fails instead
+// of waiting
+func (t *timeProvider) Checked() timeprovider.FallibleTimeProvider {
+	return (*checkedTimeProvider)(t)
+}
+
+type checkedTimeProvider timeProvider
+
+func (c *checkedTimeProvider) GetTimeStamp() (int64, error) {
+	return (*timeProvider)(c).next()
+}
+
+// next Advances the counter, which stays where it is when the state file
+// cannot be written
+func (t *timeProvider) next() (int64, error) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if err := t.advance(t.counter + 1); err != nil {
+		return t.counter, err
+	}
+	t.counter++
+	return t.counter, nil
+}
+
+// Observe Fast-forwards the counter to a timestamp received from another
+// node, so every later timestamp is greater than it. Timestamps outside the
+// timestamp field of the layout are rejected with ErrOutOfRange.
+func (t *timeProvider) Observe(timestamp int64) error {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if timestamp < 0 || timestamp > t.maxTimeStamp {
+		return ErrOutOfRange
+	}
+	if timestamp <= t.counter {
+		return nil
+	}
+	if err := t.advance(timestamp); err != nil {
+		return err
+	}
+	t.counter = timestamp
+	return nil
+}
+
+// Last Returns the latest timestamp returned or observed, for passing on to
+// other nodes
+func (t *timeProvider) Last() int64 {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	return t.counter
+}
+
+// Err Returns the error of the last failed write of the state file, nil once
+// a write succeeds again
+func (t *timeProvider) Err() error {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	return t.err
+}
+
+// advance Makes sure the state file covers timestamp, reserving the next
+// block when it does not. Blocks end at the largest timestamp that fits.
+func (t *timeProvider) advance(timestamp int64) error {
+	if t.path == "" || timestamp <= t.reserved {
+		return nil
+	}
+	reserved := timestamp + min(t.reserve, t.maxTimeStamp-timestamp+1) - 1
+	t.err = writeState(t.path, reserved)
+	if t.err == nil {
+		t.reserved = reserved
+	}
+	return t.err
+}
+
+// writeState Replaces the state file atomically, so a crash leaves either
+// the old or the new counter. The directory is synced after the rename, so
+// the new counter survives a power loss once the write returned.
+func writeState(path string, counter int64) error {
+	file, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file.Name())
+
+	if _, err := file.WriteString(strconv.FormatInt(counter, 10) + "\n"); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Sync(); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(file.Name(), path); err != nil {
+		return err
+	}
+
+	dir, err := os.Open(filepath.Dir(path))
+	if err != nil {
+		return err
+	}
+	defer dir.Close()
+	return dir.Sync()
+}
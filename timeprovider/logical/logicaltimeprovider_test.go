@@ -0,0 +1,176 @@
+package logical
+
+import (
+	"errors"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestGetTimeStamp_InMemory(t *testing.T) {
+	provider, err := New("")
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	for i := int64(1); i <= 5; i++ {
+		if timestamp := provider.GetTimeStamp(); timestamp != i {
+			t.Errorf("Expected %d, got %d", i, timestamp)
+		}
+	}
+}
+
+func TestGetTimeStamp_PersistsReservedBlocks(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "counter")
+	provider, err := New(path, WithReserve(10))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+
+	provider.GetTimeStamp()
+	if data, _ := os.ReadFile(path); string(data) != "10\n" {
+		t.Errorf("Expected 10 reserved, got %q", data)
+	}
+
+	for i := 0; i < 10; i++ {
+		provider.GetTimeStamp()
+	}
+	if data, _ := os.ReadFile(path); string(data) != "20\n" {
+		t.Errorf("Expected 20 reserved, got %q", data)
+	}
+}
+
+func TestNew_RestartNeverGoesBackwards(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "counter")
+	provider, _ := New(path, WithReserve(100))
+
+	var last int64
+	for i := 0; i < 150; i++ {
+		last = provider.GetTimeStamp()
+	}
+
+	// Simulates a crash, nothing is written on shutdown
+	restarted, err := New(path, WithReserve(100))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if timestamp := restarted.GetTimeStamp(); timestamp <= last {
+		t.Errorf("Expected %d after restart to be greater than %d", timestamp, last)
+	}
+}
+
+func TestNew_InvalidState(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "counter")
+	for _, content := range []string{"abc", "-5", ""} {
+		os.WriteFile(path, []byte(content), 0600)
+		if _, err := New(path); !errors.Is(err, ErrInvalidState) {
+			t.Errorf("Expected ErrInvalidState for %q, got %v", content, err)
+		}
+	}
+}
+
+func TestObserve(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "counter")
+	provider, _ := New(path, WithReserve(10))
+	provider.GetTimeStamp()
+
+	if err := provider.Observe(500); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+	if last := provider.Last(); last != 500 {
+		t.Errorf("Expected 500, got %d", last)
+	}
+	if timestamp := provider.GetTimeStamp(); timestamp != 501 {
+		t.Errorf("Expected 501, got %d", timestamp)
+	}
+	if data, _ := os.ReadFile(path); string(data) != "509\n" {
+		t.Errorf("Expected 509 reserved, got %q", data)
+	}
+
+	// Older timestamps do not move the counter
+	if err := provider.Observe(100); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+	if timestamp := provider.GetTimeStamp(); timestamp != 502 {
+		t.Errorf("Expected 502, got %d", timestamp)
+	}
+}
+
+func TestObserve_OutOfRange(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "counter")
+	provider, _ := New(path, WithReserve(10))
+	provider.GetTimeStamp()
+
+	for _, timestamp := range []int64{-1, 1 << 41, math.MaxInt64 - 10, math.MaxInt64} {
+		if err := provider.Observe(timestamp); err != ErrOutOfRange {
+			t.Errorf("Expected ErrOutOfRange for %d, got %v", timestamp, err)
+		}
+	}
+	if data, _ := os.ReadFile(path); string(data) != "10\n" {
+		t.Errorf("Expected the state file to be unchanged, got %q", data)
+	}
+	if last := provider.Last(); last != 1 {
+		t.Errorf("Expected 1, got %d", last)
+	}
+
+	// Blocks near the end of the range stop at its last timestamp
+	if err := provider.Observe(1<<41 - 5); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+	if data, _ := os.ReadFile(path); string(data) != "2199023255551\n" {
+		t.Errorf("Expected 2199023255551 reserved, got %q", data)
+	}
+	if _, err := New(path); err != nil {
+		t.Errorf("Expected the state file to load after a restart, got %v", err)
+	}
+}
+
+func TestGetTimeStamp_StopsWhenStateCannotBeWritten(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "state")
+	os.Mkdir(dir, 0700)
+	provider, _ := New(filepath.Join(dir, "counter"), WithReserve(2))
+	provider.GetTimeStamp()
+	provider.GetTimeStamp()
+
+	// The directory disappears, so the next block cannot be reserved
+	os.RemoveAll(dir)
+	if timestamp := provider.GetTimeStamp(); timestamp != 2 {
+		t.Errorf("Expected the counter to stay at 2, got %d", timestamp)
+	}
+	if provider.Err() == nil {
+		t.Error("Expected a write error, got nil")
+	}
+	if err := provider.Observe(10); err == nil {
+		t.Error("Expected Observe to fail, got nil")
+	}
+
+	os.Mkdir(dir, 0700)
+	if timestamp := provider.GetTimeStamp(); timestamp != 3 {
+		t.Errorf("Expected 3 once the state can be written, got %d", timestamp)
+	}
+	if err := provider.Err(); err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+}
+
+func TestChecked_ReturnsWriteErrors(t *testing.T) {
+	dir := filepath.Join(t.TempDir(), "state")
+	os.Mkdir(dir, 0700)
+	provider, _ := New(filepath.Join(dir, "counter"), WithReserve(1))
+	checked := provider.Checked()
+
+	if timestamp, err := checked.GetTimeStamp(); timestamp != 1 || err != nil {
+		t.Errorf("Expected 1 without error, got %d and %v", timestamp, err)
+	}
+
+	os.RemoveAll(dir)
+	if _, err := checked.GetTimeStamp(); err == nil {
+		t.Error("Expected the write error, got nil")
+	}
+
+	os.Mkdir(dir, 0700)
+	if timestamp, err := checked.GetTimeStamp(); timestamp != 2 || err != nil {
+		t.Errorf("Expected 2 without error, got %d and %v", timestamp, err)
+	}
+}
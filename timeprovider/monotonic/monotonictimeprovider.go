@@ -0,0 +1,108 @@
+package monotonic
+
+import (
+	"sync"
+	"time"
+)
+
+// DefaultMaxSlew is the largest rate at which a resync corrects the clock,
+// 500 ppm like ntpd's slewing
+const DefaultMaxSlew = 0.0005
+
+// TimeProvider Implements the TimeProvider interface on the monotonic clock.
+// The wall clock is read once when the provider is created and the time then
+// advances with the monotonic clock, so NTP steps and manual changes of the
+// system clock are not visible to the generator.
+type timeProvider struct {
+	epochOffset    int64
+	resyncInterval time.Duration
+	maxSlew        float64
+
+	anchor     int64                // Wall clock in nanoseconds at creation
+	since      func() time.Duration // Monotonic time elapsed since creation
+	wall       func() time.Time     // Wall clock read when resyncing
+	mutex      sync.Mutex
+	correction int64         // Nanoseconds added by resyncs up to the last one
+	slew       int64         // Nanoseconds spread over the interval after the last resync
+	lastSync   time.Duration // Elapsed time of the last resync
+	last       int64         // Last returned timestamp
+}
+
+// Option Configures the monotonic TimeProvider
+type Option func(*timeProvider)
+
+// WithResync Compares the clock with the wall clock every interval and slews
+// it towards it over the following interval, running at most maxSlew faster
+// or slower than the monotonic clock, e.g. 0.0005 for 0.5 ms per second. maxSlew must be below 1 to keep the clock increasing;
+// DefaultMaxSlew is used when it is not.
+func WithResync(interval time.Duration, maxSlew float64) Option {
+	return func(t *timeProvider) {
+		t.resyncInterval = interval
+		if maxSlew <= 0 || maxSlew >= 1 {
+			maxSlew = DefaultMaxSlew
+		}
+		t.maxSlew = maxSlew
+	}
+}
+
+// New TimeProvider anchored to the current wall clock
+func New(epochOffset int64, opts ...Option) *timeProvider {
+	start := time.Now()
+	t := &timeProvider{
+		epochOffset: epochOffset,
+		anchor:      start.UnixNano(),
+		since:       func() time.Duration { return time.Since(start) },
+		wall:        time.Now,
+	}
+	for _, opt := range opts {
+		opt(t)
+	}
+	return t
+}
+
+// GetTimeStamp Returns the current time in milliseconds
+func (t *timeProvider) GetTimeStamp() int64 {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	elapsed := t.since()
+	if t.resyncInterval > 0 && elapsed-t.lastSync >= t.resyncInterval {
+		t.resync(elapsed)
+	}
+
+	timestamp := (t.anchor+int64(elapsed)+t.corrected(elapsed))/int64(time.Millisecond) - t.epochOffset
+	if timestamp < t.last {
+		// Slewing keeps the clock increasing, this only absorbs rounding
+		timestamp = t.last
+	}
+	t.last = timestamp
+	return timestamp
+}
+
+// Drift Returns how far the wall clock is ahead of the provider
+func (t *timeProvider) Drift() time.Duration {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	elapsed := t.since()
+	return time.Duration(t.wall().UnixNano() - (t.anchor + int64(elapsed) + t.corrected(elapsed)))
+}
+
+// corrected Returns the correction at elapsed: the slew of the last resync is
+// added in proportion to the time since, completing one interval later
+func (t *timeProvider) corrected(elapsed time.Duration) int64 {
+	if t.slew == 0 {
+		return t.correction
+	}
+	progress := min(elapsed-t.lastSync, t.resyncInterval)
+	return t.correction + int64(float64(t.slew)*float64(progress)/float64(t.resyncInterval))
+}
+
+// resync Measures the drift to the wall clock and spreads its correction
+// over the next interval, by no more than maxSlew of it
+func (t *timeProvider) resync(elapsed time.Duration) {
+	t.correction = t.corrected(elapsed)
+	drift := t.wall().UnixNano() - (t.anchor + int64(elapsed) + t.correction)
+	maxSlew := int64(float64(t.resyncInterval) * t.maxSlew)
+	t.slew = min(max(drift, -maxSlew), maxSlew)
+	t.lastSync = elapsed
+}
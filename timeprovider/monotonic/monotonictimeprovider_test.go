@@ -0,0 +1,146 @@
+package monotonic
+
+import (
+	"testing"
+	"time"
+)
+
+// fakeClocks Lets a test move the monotonic and the wall clock independently
+type fakeClocks struct {
+	elapsed time.Duration
+	wall    time.Time
+}
+
+func newTestProvider(clocks *fakeClocks, opts ...Option) *timeProvider {
+	provider := New(0, opts...)
+	provider.anchor = clocks.wall.UnixNano()
+	provider.since = func() time.Duration { return clocks.elapsed }
+	provider.wall = func() time.Time { return clocks.wall }
+	return provider
+}
+
+func TestNew(t *testing.T) {
+	offset := int64(1420070400000)
+	provider := New(offset)
+
+	if provider.epochOffset != offset {
+		t.Errorf("Expected offset %d, got %d", offset, provider.epochOffset)
+	}
+	if provider.resyncInterval != 0 {
+		t.Errorf("Expected no resync, got %v", provider.resyncInterval)
+	}
+}
+
+func TestGetTimeStamp_MatchesWallClockAtStart(t *testing.T) {
+	provider := New(0)
+	before := time.Now().UnixMilli()
+	timestamp := provider.GetTimeStamp()
+	after := time.Now().UnixMilli()
+
+	if timestamp < before-1 || timestamp > after {
+		t.Errorf("Expected %d to be between %d and %d", timestamp, before-1, after)
+	}
+}
+
+func TestGetTimeStamp_IgnoresWallClockSteps(t *testing.T) {
+	clocks := &fakeClocks{wall: time.UnixMilli(1_000_000)}
+	provider := newTestProvider(clocks)
+
+	clocks.elapsed = time.Second
+	clocks.wall = time.UnixMilli(1_000_000 - 60_000) // Stepped back a minute
+	if timestamp := provider.GetTimeStamp(); timestamp != 1_001_000 {
+		t.Errorf("Expected 1001000, got %d", timestamp)
+	}
+
+	clocks.elapsed = 2 * time.Second
+	clocks.wall = time.UnixMilli(1_000_000 + 3_600_000) // Stepped forward an hour
+	if timestamp := provider.GetTimeStamp(); timestamp != 1_002_000 {
+		t.Errorf("Expected 1002000, got %d", timestamp)
+	}
+}
+
+func TestGetTimeStamp_ResyncSlewsTowardsWallClock(t *testing.T) {
+	clocks := &fakeClocks{wall: time.UnixMilli(1_000_000)}
+	provider := newTestProvider(clocks, WithResync(time.Second, 0.001))
+
+	// The wall clock jumps 10 ms ahead, every resync spreads 1 ms over the
+	// next second
+	clocks.wall = clocks.wall.Add(10 * time.Millisecond)
+	for i := 1; i <= 20; i++ {
+		clocks.elapsed += time.Second
+		clocks.wall = clocks.wall.Add(time.Second)
+
+		expected := 1_000_000 + int64(i)*1000 + min(int64(i)-1, 10)
+		if timestamp := provider.GetTimeStamp(); timestamp != expected {
+			t.Errorf("Expected %d after %d resyncs, got %d", expected, i, timestamp)
+		}
+	}
+	if drift := provider.Drift(); drift != 0 {
+		t.Errorf("Expected no drift, got %v", drift)
+	}
+}
+
+func TestGetTimeStamp_ResyncNeverGoesBackwards(t *testing.T) {
+	clocks := &fakeClocks{wall: time.UnixMilli(1_000_000)}
+	provider := newTestProvider(clocks, WithResync(time.Millisecond, 0.5))
+
+	// The wall clock is stepped back an hour
+	clocks.wall = clocks.wall.Add(-time.Hour)
+	var previous int64
+	for i := 0; i < 10000; i++ {
+		clocks.elapsed += time.Millisecond
+		clocks.wall = clocks.wall.Add(time.Millisecond)
+
+		timestamp := provider.GetTimeStamp()
+		if timestamp < previous {
+			t.Fatalf("Expected %d to not be less than %d", timestamp, previous)
+		}
+		previous = timestamp
+	}
+	if drift := provider.Drift(); drift >= 0 || drift < -time.Hour+4*time.Second {
+		t.Errorf("Expected a drift of about -59m55s, got %v", drift)
+	}
+}
+
+func TestGetTimeStamp_ResyncSlewsGradually(t *testing.T) {
+	clocks := &fakeClocks{wall: time.UnixMilli(1_000_000)}
+	provider := newTestProvider(clocks, WithResync(time.Minute, DefaultMaxSlew))
+
+	// The wall clock is stepped back an hour and the clock is read every
+	// millisecond, so each resync slews by 30 ms over the following minute
+	clocks.wall = clocks.wall.Add(-time.Hour)
+	previous := provider.GetTimeStamp()
+	repeats := 0
+	for i := 0; i < 3*60*1000; i++ {
+		clocks.elapsed += time.Millisecond
+		clocks.wall = clocks.wall.Add(time.Millisecond)
+
+		timestamp := provider.GetTimeStamp()
+		switch {
+		case timestamp < previous:
+			t.Fatalf("Expected %d to not be less than %d", timestamp, previous)
+		case timestamp == previous:
+			repeats++
+			if repeats > 1 {
+				t.Fatalf("Expected the clock to stall for at most one read, got %d at %v", repeats, clocks.elapsed)
+			}
+		default:
+			repeats = 0
+		}
+		previous = timestamp
+	}
+
+	// Two full minutes of slewing
+	if expected := 1_000_000 + 3*60*1000 - 60; previous != int64(expected) {
+		t.Errorf("Expected %d, got %d", expected, previous)
+	}
+}
+
+func TestWithResync_InvalidSlew(t *testing.T) {
+	for _, slew := range []float64{0, -1, 1, 2} {
+		provider := New(0, WithResync(time.Second, slew))
+		if provider.maxSlew != DefaultMaxSlew {
+			t.Errorf("Expected slew %v for %v, got %v", DefaultMaxSlew, slew, provider.maxSlew)
+		}
+	}
+}
@@ -0,0 +1,133 @@
+package tai
+
+import (
+	"bufio"
+	"bytes"
+	_ "embed"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ntpEpoch is 1900-01-01 in Unix seconds, the epoch of leap-seconds.list
+const ntpEpoch = -2208988800
+
+//go:embed leap-seconds.list
+var embeddedTable []byte
+
+var ErrInvalidTable = errors.New("invalid leap second table")
+
+// LeapSecond Is an instant from which TAI is a new number of seconds ahead
+// of UTC
+type LeapSecond struct {
+	At          time.Time // Start of the new difference, in UTC
+	TAIMinusUTC int64     // Difference in seconds
+}
+
+// Table Holds the leap seconds in the order they took effect
+type Table struct {
+	leaps   []LeapSecond
+	expires time.Time
+}
+
+// DefaultTable Returns the leap second table embedded in the binary
+func DefaultTable() *Table {
+	table, err := ParseTable(bytes.NewReader(embeddedTable))
+	if err != nil {
+		panic(err)
+	}
+	return table
+}
+
+// LoadTable Reads a leap second table in the leap-seconds.list format, so a
+// newer list can be used without rebuilding
+func LoadTable(path string) (*Table, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+	return ParseTable(file)
+}
+
+// ParseTable Parses a table in the IETF leap-seconds.list format: lines of
+// NTP seconds and the new TAI-UTC difference, comments starting with # and
+// the expiry date on a line starting with #@
+func ParseTable(r io.Reader) (*Table, error) {
+	table := &Table{}
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if expires, ok := strings.CutPrefix(text, "#@"); ok {
+			seconds, err := strconv.ParseInt(strings.TrimSpace(expires), 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("%w: line %d", ErrInvalidTable, line)
+			}
+			table.expires = time.Unix(seconds+ntpEpoch, 0).UTC()
+			continue
+		}
+		if text, _, _ = strings.Cut(text, "#"); strings.TrimSpace(text) == "" {
+			continue
+		}
+
+		fields := strings.Fields(text)
+		if len(fields) != 2 {
+			return nil, fmt.Errorf("%w: line %d", ErrInvalidTable, line)
+		}
+		seconds, err := strconv.ParseInt(fields[0], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%w: line %d", ErrInvalidTable, line)
+		}
+		difference, err := strconv.ParseInt(fields[1], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%w: line %d", ErrInvalidTable, line)
+		}
+		leap := LeapSecond{At: time.Unix(seconds+ntpEpoch, 0).UTC(), TAIMinusUTC: difference}
+		if n := len(table.leaps); n > 0 && !leap.At.After(table.leaps[n-1].At) {
+			return nil, fmt.Errorf("%w: line %d is out of order", ErrInvalidTable, line)
+		}
+		table.leaps = append(table.leaps, leap)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(table.leaps) == 0 {
+		return nil, fmt.Errorf("%w: no leap seconds", ErrInvalidTable)
+	}
+	return table, nil
+}
+
+// LeapSeconds Returns the leap seconds of the table
+func (t *Table) LeapSeconds() []LeapSecond {
+	return append([]LeapSecond(nil), t.leaps...)
+}
+
+// Expires Returns when the table stops being authoritative, zero when the
+// list did not say
+func (t *Table) Expires() time.Time {
+	return t.expires
+}
+
+// Expired Reports whether a newer table should be loaded
+func (t *Table) Expired(now time.Time) bool {
+	return !t.expires.IsZero() && now.After(t.expires)
+}
+
+// index Returns the position of the leap second in effect at unixMilli, or
+// -1 before the first one
+func (t *Table) index(unixMilli int64) int {
+	return sort.Search(len(t.leaps), func(i int) bool {
+		return t.leaps[i].At.UnixMilli() > unixMilli
+	}) - 1
+}
+
+// TAIMinusUTC Returns how many seconds TAI is ahead of UTC at the given UTC
+// time. Before 1972 the first difference of the table is used.
+func (t *Table) TAIMinusUTC(unixMilli int64) int64 {
+	return t.leaps[max(t.index(unixMilli), 0)].TAIMinusUTC
+}
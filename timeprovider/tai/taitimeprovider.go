@@ -0,0 +1,143 @@
+package tai
+
+import (
+	"sync"
+	"time"
+)
+
+// GPSMinusTAI is the constant difference of GPS time to TAI in milliseconds
+const GPSMinusTAI = -19000
+
+// smearWindow is the length of a leap smear, centred on the leap second
+const smearWindow = 24 * time.Hour
+
+// Scale Selects the time scale of the timestamps
+type Scale int
+
+const (
+	TAI Scale = iota // International Atomic Time, without leap seconds
+	GPS              // GPS time, 19 seconds behind TAI
+)
+
+// TimeProvider Implements the TimeProvider interface on a time scale without
+// leap seconds. The system clock follows UTC, which repeats a second when a
+// leap second is inserted; the provider adds the TAI-UTC difference of its
+// leap second table and recognises the repeated second, so timestamps keep
+// advancing through it.
+type timeProvider struct {
+	epochOffset int64
+	scale       Scale
+	table       *Table
+	smear       bool
+	now         func() time.Time
+
+	mutex sync.Mutex
+	last  int64 // Last TAI reading in Unix milliseconds
+}
+
+// Option Configures the TAI TimeProvider
+type Option func(*timeProvider)
+
+// WithScale Selects TAI or GPS timestamps, TAI by default
+func WithScale(scale Scale) Option {
+	return func(t *timeProvider) {
+		t.scale = scale
+	}
+}
+
+// WithTable Uses a leap second table other than the embedded one, such as a
+// newer list loaded with LoadTable
+func WithTable(table *Table) Option {
+	return func(t *timeProvider) {
+		t.table = table
+	}
+}
+
+// WithLeapSmear Returns UTC instead, with every leap second spread linearly
+// over the 24 hours around it, noon to noon, like the public NTP servers of
+// Google and Amazon. Timestamps stay in step with UTC outside the smear and
+// never repeat or skip a millisecond. The scale is ignored.
+func WithLeapSmear() Option {
+	return func(t *timeProvider) {
+		t.smear = true
+	}
+}
+
+// New TimeProvider based on provided epoch, in UTC milliseconds
+func New(epochOffset int64, opts ...Option) *timeProvider {
+	t := &timeProvider{
+		epochOffset: epochOffset,
+		now:         time.Now,
+	}
+	for _, opt := range opts {
+		opt(t)
+	}
+	if t.table == nil {
+		t.table = DefaultTable()
+	}
+	return t
+}
+
+// GetTimeStamp Returns the milliseconds since the offset on the configured
+// scale
+func (t *timeProvider) GetTimeStamp() int64 {
+	tai := t.tai(t.now().UnixMilli())
+	switch {
+	case t.smear:
+		return t.smeared(tai) - t.epochOffset
+	case t.scale == GPS:
+		return tai + GPSMinusTAI - t.epochOffset
+	default:
+		return tai - t.epochOffset
+	}
+}
+
+// Table Returns the leap second table in use
+func (t *timeProvider) Table() *Table {
+	return t.table
+}
+
+// tai Converts a reading of the system clock to TAI. When UTC goes back
+// over an inserted leap second, the repeated second is 23:59:60 and is
+// moved past the first one.
+func (t *timeProvider) tai(unixMilli int64) int64 {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	tai := unixMilli + t.table.TAIMinusUTC(unixMilli)*1000
+	if tai < t.last {
+		if next := t.table.index(unixMilli) + 1; next > 0 && next < len(t.table.leaps) {
+			leap := t.table.leaps[next]
+			inserted := (leap.TAIMinusUTC - t.table.leaps[next-1].TAIMinusUTC) * 1000
+			if inserted > 0 && unixMilli >= leap.At.UnixMilli()-inserted {
+				tai += inserted
+			}
+		}
+	}
+	t.last = max(t.last, tai)
+	return tai
+}
+
+// smeared Converts TAI to UTC in Unix milliseconds, spreading leap seconds
+// over the smear window around them
+func (t *timeProvider) smeared(tai int64) int64 {
+	window := smearWindow.Milliseconds()
+	for i := 1; i < len(t.table.leaps); i++ {
+		before, leap := t.table.leaps[i-1], t.table.leaps[i]
+		end := leap.At.UnixMilli() + leap.TAIMinusUTC*1000 + window/2
+		if tai >= end-window && tai < end {
+			step := (leap.TAIMinusUTC - before.TAIMinusUTC) * 1000
+			return tai - before.TAIMinusUTC*1000 - step*(tai-(end-window))/window
+		}
+	}
+
+	// Outside the windows the difference of the latest leap second before
+	// the time applies
+	for i := len(t.table.leaps) - 1; i > 0; i-- {
+		leap := t.table.leaps[i]
+		if utc := tai - leap.TAIMinusUTC*1000; utc >= leap.At.UnixMilli() {
+			return utc
+		}
+	}
+	return tai - t.table.leaps[0].TAIMinusUTC*1000
+}
@@ -0,0 +1,184 @@
+package tai
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+// leap2016 is the UTC instant the leap second after 2016-12-31 23:59:60 ended
+var leap2016 = time.Date(2017, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// scriptedClock Returns the given system clock readings in order, repeating
+// the last one
+type scriptedClock struct {
+	readings []time.Time
+	calls    int
+}
+
+func (c *scriptedClock) now() time.Time {
+	i := min(c.calls, len(c.readings)-1)
+	c.calls++
+	return c.readings[i]
+}
+
+func newTestProvider(clock *scriptedClock, opts ...Option) *timeProvider {
+	provider := New(0, opts...)
+	provider.now = clock.now
+	return provider
+}
+
+func TestDefaultTable(t *testing.T) {
+	table := DefaultTable()
+	leaps := table.LeapSeconds()
+
+	first := LeapSecond{At: time.Date(1972, 1, 1, 0, 0, 0, 0, time.UTC), TAIMinusUTC: 10}
+	if leaps[0] != first {
+		t.Errorf("Expected first leap second %+v, got %+v", first, leaps[0])
+	}
+	last := LeapSecond{At: leap2016, TAIMinusUTC: 37}
+	if leaps[len(leaps)-1] != last {
+		t.Errorf("Expected last leap second %+v, got %+v", last, leaps[len(leaps)-1])
+	}
+	if expires := time.Date(2026, 6, 28, 0, 0, 0, 0, time.UTC); !table.Expires().Equal(expires) {
+		t.Errorf("Expected expiry %v, got %v", expires, table.Expires())
+	}
+}
+
+func TestTable_TAIMinusUTC(t *testing.T) {
+	table := DefaultTable()
+	testCases := []struct {
+		utc      time.Time
+		expected int64
+	}{
+		{time.Date(1970, 1, 1, 0, 0, 0, 0, time.UTC), 10},
+		{time.Date(2016, 12, 31, 23, 59, 59, 999000000, time.UTC), 36},
+		{leap2016, 37},
+		{time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), 37},
+	}
+
+	for _, tc := range testCases {
+		if actual := table.TAIMinusUTC(tc.utc.UnixMilli()); actual != tc.expected {
+			t.Errorf("Expected %d at %v, got %d", tc.expected, tc.utc, actual)
+		}
+	}
+}
+
+func TestParseTable(t *testing.T) {
+	list := "# Comment\n#@\t3692217600\n2272060800\t10\t# 1 Jan 1972\n\n3692217600 37\n"
+	table, err := ParseTable(strings.NewReader(list))
+	if err != nil {
+		t.Fatalf("Expected no error, got %v", err)
+	}
+	if n := len(table.LeapSeconds()); n != 2 {
+		t.Errorf("Expected 2 leap seconds, got %d", n)
+	}
+	if !table.Expired(leap2016.Add(time.Second)) || table.Expired(leap2016.Add(-time.Second)) {
+		t.Errorf("Expected the table to expire at %v, got %v", leap2016, table.Expires())
+	}
+}
+
+func TestParseTable_Invalid(t *testing.T) {
+	for _, list := range []string{
+		"",
+		"# Only comments\n",
+		"2272060800\n",
+		"2272060800 ten\n",
+		"3692217600 37\n2272060800 10\n",
+		"#@ soon\n2272060800 10\n",
+	} {
+		if _, err := ParseTable(strings.NewReader(list)); !errors.Is(err, ErrInvalidTable) {
+			t.Errorf("Expected ErrInvalidTable for %q, got %v", list, err)
+		}
+	}
+}
+
+func TestGetTimeStamp_TAIAndGPS(t *testing.T) {
+	utc := time.Date(2024, 5, 6, 7, 8, 9, 0, time.UTC)
+	clock := &scriptedClock{readings: []time.Time{utc}}
+
+	if timestamp := newTestProvider(clock).GetTimeStamp(); timestamp != utc.UnixMilli()+37000 {
+		t.Errorf("Expected TAI 37s ahead of UTC, got %d", timestamp-utc.UnixMilli())
+	}
+	if timestamp := newTestProvider(clock, WithScale(GPS)).GetTimeStamp(); timestamp != utc.UnixMilli()+18000 {
+		t.Errorf("Expected GPS 18s ahead of UTC, got %d", timestamp-utc.UnixMilli())
+	}
+}
+
+// systemClockAround2016 Reads the system clock every 250ms from 23:59:58 to
+// 00:00:02 across the leap second of 2016, the way the kernel inserts it:
+// 23:59:59 is repeated
+func systemClockAround2016() []time.Time {
+	var readings []time.Time
+	second := func(s time.Time) {
+		for ms := 0; ms < 1000; ms += 250 {
+			readings = append(readings, s.Add(time.Duration(ms)*time.Millisecond))
+		}
+	}
+	second(leap2016.Add(-2 * time.Second))
+	second(leap2016.Add(-time.Second))
+	second(leap2016.Add(-time.Second)) // The leap second, 23:59:60
+	second(leap2016)
+	second(leap2016.Add(time.Second))
+	return readings
+}
+
+func TestGetTimeStamp_AcrossLeapSecond2016(t *testing.T) {
+	readings := systemClockAround2016()
+	provider := newTestProvider(&scriptedClock{readings: readings})
+
+	// Every reading is 250ms after the previous one in TAI
+	start := leap2016.Add(-2*time.Second).UnixMilli() + 36000
+	for i := range readings {
+		expected := start + int64(i)*250
+		if timestamp := provider.GetTimeStamp(); timestamp != expected {
+			t.Errorf("Expected %d for reading %d at %v, got %d", expected, i, readings[i], timestamp)
+		}
+	}
+}
+
+func TestGetTimeStamp_LeapSmear(t *testing.T) {
+	clock := &scriptedClock{}
+	provider := newTestProvider(clock, WithLeapSmear())
+
+	noon := time.Date(2016, 12, 31, 12, 0, 0, 0, time.UTC)
+	testCases := []struct {
+		utc      time.Time
+		expected time.Time
+	}{
+		// Before the smear
+		{noon.Add(-time.Hour), noon.Add(-time.Hour)},
+		{noon, noon},
+		// Half way, at the end of the leap second, the smeared clock is half
+		// a second ahead of UTC and half a second behind a clock without it
+		{leap2016, leap2016.Add(500 * time.Millisecond)},
+		// Three quarters of the way
+		{leap2016.Add(6 * time.Hour), leap2016.Add(6*time.Hour + 250*time.Millisecond)},
+		// After the smear
+		{noon.Add(24 * time.Hour), noon.Add(24 * time.Hour)},
+		{noon.Add(25 * time.Hour), noon.Add(25 * time.Hour)},
+	}
+
+	for _, tc := range testCases {
+		clock.readings = []time.Time{tc.utc}
+		clock.calls = 0
+		if timestamp := provider.GetTimeStamp(); timestamp != tc.expected.UnixMilli() {
+			t.Errorf("Expected %v at %v, got %v", tc.expected, tc.utc, time.UnixMilli(timestamp).UTC())
+		}
+	}
+}
+
+func TestGetTimeStamp_LeapSmearNeverRepeats(t *testing.T) {
+	readings := systemClockAround2016()
+	provider := newTestProvider(&scriptedClock{readings: readings}, WithLeapSmear())
+
+	var previous int64
+	for i := range readings {
+		timestamp := provider.GetTimeStamp()
+		if i > 0 && (timestamp <= previous || timestamp-previous > 250) {
+			t.Errorf("Expected reading %d to advance by at most 250ms from %d, got %d", i, previous, timestamp)
+		}
+		previous = timestamp
+	}
+}
@@ -3,3 +3,32 @@ package timeprovider
 type TimeProvider interface {
 	GetTimeStamp() int64
 }
+
+// FallibleTimeProvider Is the variant of TimeProvider for clocks that can
+// fail, such as ones reading an external source
+type FallibleTimeProvider interface {
+	GetTimeStamp() (int64, error)
+}
+
+// Checker Is implemented by providers that can also report their errors
+// through a FallibleTimeProvider
+type Checker interface {
+	Checked() FallibleTimeProvider
+}
+
+// Adapt Returns provider as a FallibleTimeProvider, using its checked
+// variant when it has one and otherwise one that never fails
+func Adapt(provider TimeProvider) FallibleTimeProvider {
+	if checker, ok := provider.(Checker); ok {
+		return checker.Checked()
+	}
+	return infallible{provider}
+}
+
+type infallible struct {
+	provider TimeProvider
+}
+
+func (i infallible) GetTimeStamp() (int64, error) {
+	return i.provider.GetTimeStamp(), nil
+}
@@ -0,0 +1,44 @@
+package timeprovider
+
+import (
+	"errors"
+	"testing"
+)
+
+var errBroken = errors.New("broken")
+
+type constantProvider int64
+
+func (p constantProvider) GetTimeStamp() int64 {
+	return int64(p)
+}
+
+type checkedProvider struct {
+	constantProvider
+}
+
+func (p checkedProvider) Checked() FallibleTimeProvider {
+	return brokenProvider{}
+}
+
+type brokenProvider struct{}
+
+func (brokenProvider) GetTimeStamp() (int64, error) {
+	return 0, errBroken
+}
+
+func TestAdapt(t *testing.T) {
+	timestamp, err := Adapt(constantProvider(42)).GetTimeStamp()
+	if err != nil {
+		t.Errorf("Expected no error, got %v", err)
+	}
+	if timestamp != 42 {
+		t.Errorf("Expected 42, got %d", timestamp)
+	}
+}
+
+func TestAdapt_Checker(t *testing.T) {
+	if _, err := Adapt(checkedProvider{42}).GetTimeStamp(); err != errBroken {
+		t.Errorf("Expected the checked variant to be used, got %v", err)
+	}
+}